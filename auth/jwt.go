@@ -7,7 +7,27 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("your-secret-key-change-this-in-production") // In production, use environment variable
+// Config holds token signing configuration
+type Config struct {
+	JWTSecret string        `yaml:"jwtSecret"` // HMAC key for signing tokens
+	TokenTTL  time.Duration `yaml:"tokenTTL"`  // How long issued tokens stay valid
+}
+
+// DefaultConfig returns the default token configuration; it has no secret
+func DefaultConfig() *Config {
+	return &Config{TokenTTL: 24 * time.Hour}
+}
+
+var (
+	jwtSecret []byte
+	tokenTTL  = 24 * time.Hour
+)
+
+// Init sets the token signing configuration
+func Init(config *Config) {
+	jwtSecret = []byte(config.JWTSecret)
+	tokenTTL = config.TokenTTL
+}
 
 type Claims struct {
 	UserID   uint   `json:"user_id"`
@@ -17,7 +37,7 @@ type Claims struct {
 
 // GenerateToken creates a new JWT token for the user
 func GenerateToken(userID uint, username string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour) // Token expires in 24 hours
+	expirationTime := time.Now().Add(tokenTTL)
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
@@ -27,20 +27,45 @@ func JWTMiddleware() gin.HandlerFunc {
 		// Extract the token
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// Validate the token
-		claims, err := ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		authenticate(c, tokenString)
+	}
+}
+
+// JWTStreamMiddleware also accepts the token as a "token" query parameter,
+// since browser EventSource clients cannot set headers. Only use it for
+// streaming endpoints, as query strings end up in access logs.
+func JWTStreamMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.Query("token")
+		if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+			tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+
+		if tokenString == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header or token parameter required"})
 			c.Abort()
 			return
 		}
 
-		// Store user information in the context for use in handlers
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
+		authenticate(c, tokenString)
+	}
+}
 
-		c.Next()
+// authenticate validates the token and stores the user in the context
+func authenticate(c *gin.Context, tokenString string) {
+	// Validate the token
+	claims, err := ValidateToken(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return
 	}
+
+	// Store user information in the context for use in handlers
+	c.Set("user_id", claims.UserID)
+	c.Set("username", claims.Username)
+
+	c.Next()
 }
 
 // GetCurrentUserID extracts the current user ID from the context
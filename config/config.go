@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/logging"
+	"sykell-challenge/backend/services/broker"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/services/crawl"
+	"sykell-challenge/backend/services/events"
+	"sykell-challenge/backend/services/janitor"
+	"sykell-challenge/backend/services/taskq"
+	"sykell-challenge/backend/services/telemetry"
+	"sykell-challenge/backend/services/webhook"
+	"sykell-challenge/backend/utils/egress"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable pointing to an optional YAML config file
+const FileEnv = "CONFIG_FILE"
+
+// DevEncryptionKey is the credential encryption key used when none is
+// configured and credentials.allowDevKey is set. It is public, so it is only
+// fit for local development.
+const DevEncryptionKey = "dev-credentials-key-change-this-in-production"
+
+// Config is the complete application configuration
+type Config struct {
+	Server      ServerConfig         `yaml:"server"`
+	Auth        auth.Config          `yaml:"auth"`
+	Credentials CredentialsConfig    `yaml:"credentials"`
+	Database    db.Config            `yaml:"database"`
+	Logging     logging.Config       `yaml:"logging"`
+	Cluster     cluster.Config       `yaml:"cluster"`
+	Tracing     telemetry.Config     `yaml:"tracing"`
+	Broker      broker.Config        `yaml:"broker"`
+	TaskQueue   taskq.Config         `yaml:"taskQueue"`
+	Retry       crawl.RetryPolicy    `yaml:"retry"`
+	Egress      egress.Config        `yaml:"egress"`
+	Webhooks    webhook.Config       `yaml:"webhooks"`
+	Janitor     janitor.Config       `yaml:"janitor"`
+	Events      events.HistoryConfig `yaml:"events"`
+}
+
+// ServerConfig holds HTTP server configuration
+type ServerConfig struct {
+	Port            int           `yaml:"port"`
+	AllowedOrigins  []string      `yaml:"allowedOrigins"`  // CORS origins of the HTTP API and Socket.IO
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"` // How long open requests may take to finish on shutdown
+}
+
+// Addr returns the address the HTTP server listens on
+func (c ServerConfig) Addr() string {
+	return fmt.Sprintf("0.0.0.0:%d", c.Port)
+}
+
+// CredentialsConfig holds the encryption settings for stored secrets
+type CredentialsConfig struct {
+	EncryptionKey string `yaml:"encryptionKey"` // Passphrase for crawl credentials and webhook secrets
+	AllowDevKey   bool   `yaml:"allowDevKey"`   // Use DevEncryptionKey when no key is set; local development only
+}
+
+// Default returns the configuration used for values that are not set
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Auth:      *auth.DefaultConfig(),
+		Database:  *db.DefaultConfig(),
+		Logging:   *logging.DefaultConfig(),
+		Cluster:   *cluster.DefaultConfig(),
+		Tracing:   *telemetry.DefaultConfig(),
+		Broker:    *broker.DefaultConfig(),
+		TaskQueue: *taskq.DefaultConfig(),
+		Retry:     crawl.DefaultRetryPolicy(),
+		Egress:    *egress.DefaultConfig(),
+		Webhooks:  *webhook.DefaultConfig(),
+		Janitor:   *janitor.DefaultConfig(),
+		Events:    *events.DefaultHistoryConfig(),
+	}
+}
+
+// Load builds the configuration from the defaults, the YAML file named by
+// CONFIG_FILE if set, and environment variables, in increasing precedence.
+// All invalid values are reported together.
+func Load() (*Config, error) {
+	config := Default()
+
+	if path := os.Getenv(FileEnv); path != "" {
+		if err := config.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if config.Credentials.AllowDevKey && config.Credentials.EncryptionKey == "" {
+		config.Credentials.EncryptionKey = DevEncryptionKey
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
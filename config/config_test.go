@@ -0,0 +1,217 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"sykell-challenge/backend/services/cluster"
+)
+
+func validConfig() *Config {
+	c := Default()
+	c.Server.AllowedOrigins = []string{"http://localhost:3000"}
+	c.Auth.JWTSecret = strings.Repeat("s", minJWTSecretLength)
+	c.Credentials.EncryptionKey = strings.Repeat("k", minEncryptionKeyLength)
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   []string // Expected problems; none means valid
+	}{
+		{name: "valid", modify: func(c *Config) {}},
+		{
+			name:   "defaults need origins, a JWT secret and an encryption key",
+			modify: func(c *Config) { *c = *Default() },
+			want: []string{
+				"server.allowedOrigins (ALLOWED_ORIGINS): is required, e.g. http://localhost:3000",
+				"auth.jwtSecret (JWT_SECRET): is required and must be at least 32 characters",
+				"credentials.encryptionKey (CREDENTIALS_ENCRYPTION_KEY): is required and must be at least 32 characters",
+			},
+		},
+		{
+			name:   "development key without opting in",
+			modify: func(c *Config) { c.Credentials.EncryptionKey = DevEncryptionKey },
+			want:   []string{"credentials.encryptionKey (CREDENTIALS_ENCRYPTION_KEY): must not be the public development key"},
+		},
+		{
+			name: "development key with opt-in",
+			modify: func(c *Config) {
+				c.Credentials.EncryptionKey = DevEncryptionKey
+				c.Credentials.AllowDevKey = true
+			},
+		},
+		{
+			name: "workers need no origins",
+			modify: func(c *Config) {
+				c.Server.AllowedOrigins = nil
+				c.Cluster.Mode = cluster.ModeWorker
+			},
+		},
+		{
+			name:   "origin without scheme",
+			modify: func(c *Config) { c.Server.AllowedOrigins = []string{"localhost:3000"} },
+			want:   []string{`server.allowedOrigins (ALLOWED_ORIGINS): "localhost:3000" is not an origin such as https://example.com`},
+		},
+		{
+			name:   "short JWT secret",
+			modify: func(c *Config) { c.Auth.JWTSecret = "secret" },
+			want:   []string{"auth.jwtSecret (JWT_SECRET): is required and must be at least 32 characters"},
+		},
+		{
+			name:   "port out of range",
+			modify: func(c *Config) { c.Server.Port = 70000 },
+			want:   []string{"server.port (PORT): must be a port between 1 and 65535"},
+		},
+		{
+			name:   "unknown mode",
+			modify: func(c *Config) { c.Cluster.Mode = "both" },
+			want:   []string{"cluster.mode (APP_MODE): must be all, api or worker"},
+		},
+		{
+			name: "max workers below workers",
+			modify: func(c *Config) {
+				c.TaskQueue.Workers = 8
+				c.TaskQueue.MaxWorkers = 4
+			},
+			want: []string{"taskQueue.maxWorkers (CRAWL_MAX_WORKERS): must be at least the worker count (8)"},
+		},
+		{
+			name:   "retry delay cap below base",
+			modify: func(c *Config) { c.Retry.MaxDelay = time.Second },
+			want:   []string{"retry.maxDelay (CRAWL_RETRY_MAX_DELAY): must be at least the base delay (2s)"},
+		},
+		{
+			name:   "stale timeout within the janitor interval",
+			modify: func(c *Config) { c.Janitor.StaleTimeout = c.Janitor.Interval },
+			want:   []string{"janitor.staleTimeout (STALE_JOB_TIMEOUT): must be longer than the janitor interval (10m0s)"},
+		},
+		{
+			name:   "invalid proxy",
+			modify: func(c *Config) { c.Egress.Proxies = []string{"ftp://proxy.example.com"} },
+			want:   []string{"egress.proxies (CRAWL_PROXIES): "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.modify(c)
+
+			err := c.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var configErr *Error
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Validate() = %v, want an *Error", err)
+			}
+			if len(configErr.Problems) != len(tt.want) {
+				t.Fatalf("Validate() problems = %q, want %q", configErr.Problems, tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(configErr.Problems[i], want) {
+					t.Errorf("problem %d = %q, want %q", i, configErr.Problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	file := `
+server:
+  port: 9090
+  allowedOrigins: ["https://app.example.com"]
+auth:
+  jwtSecret: "0123456789abcdef0123456789abcdef"
+logging:
+  level: debug
+janitor:
+  interval: 5m
+`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(FileEnv, path)
+	t.Setenv("PORT", "9191")
+	t.Setenv("ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("CREDENTIALS_ENCRYPTION_KEY", "")
+	t.Setenv("CREDENTIALS_ALLOW_DEV_KEY", "true")
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if c.Server.Port != 9191 {
+		t.Errorf("port = %d, want the environment to override the file", c.Server.Port)
+	}
+	if want := []string{"https://a.example.com", "https://b.example.com"}; !slices.Equal(c.Server.AllowedOrigins, want) {
+		t.Errorf("allowed origins = %q, want %q", c.Server.AllowedOrigins, want)
+	}
+	if c.Logging.Level != slog.LevelDebug {
+		t.Errorf("log level = %s, want DEBUG from the file", c.Logging.Level)
+	}
+	if c.Janitor.Interval != 5*time.Minute {
+		t.Errorf("janitor interval = %s, want 5m from the file", c.Janitor.Interval)
+	}
+	if c.Credentials.EncryptionKey != DevEncryptionKey {
+		t.Errorf("encryption key = %q, want the development key when opted in", c.Credentials.EncryptionKey)
+	}
+	if c.TaskQueue.Workers != Default().TaskQueue.Workers {
+		t.Errorf("workers = %d, want the default", c.TaskQueue.Workers)
+	}
+}
+
+func TestLoadRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		want string
+	}{
+		{name: "unknown file key", file: "server:\n  prot: 9090\n", want: "field prot not found"},
+		{name: "malformed number", env: map[string]string{"CRAWL_WORKERS": "many"}, want: `CRAWL_WORKERS: "many" is not a whole number`},
+		{name: "malformed duration", env: map[string]string{"SHUTDOWN_TIMEOUT": "5"}, want: "SHUTDOWN_TIMEOUT"},
+		{name: "malformed flag", env: map[string]string{"CREDENTIALS_ALLOW_DEV_KEY": "yes please"}, want: `"yes please" is not true or false`},
+		{name: "missing encryption key", env: map[string]string{"CREDENTIALS_ENCRYPTION_KEY": ""}, want: "credentials.encryptionKey"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ALLOWED_ORIGINS", "http://localhost:3000")
+			t.Setenv("JWT_SECRET", strings.Repeat("s", minJWTSecretLength))
+			t.Setenv("CREDENTIALS_ENCRYPTION_KEY", strings.Repeat("k", minEncryptionKeyLength))
+			t.Setenv(FileEnv, "")
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv(FileEnv, path)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := Load()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Load() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting ties a configuration value to its environment variable and YAML key
+type setting struct {
+	env    string
+	key    string
+	target any
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"PORT", "server.port", &c.Server.Port},
+		{"ALLOWED_ORIGINS", "server.allowedOrigins", &c.Server.AllowedOrigins},
+		{"SHUTDOWN_TIMEOUT", "server.shutdownTimeout", &c.Server.ShutdownTimeout},
+
+		{"JWT_SECRET", "auth.jwtSecret", &c.Auth.JWTSecret},
+		{"JWT_TOKEN_TTL", "auth.tokenTTL", &c.Auth.TokenTTL},
+		{"CREDENTIALS_ENCRYPTION_KEY", "credentials.encryptionKey", &c.Credentials.EncryptionKey},
+		{"CREDENTIALS_ALLOW_DEV_KEY", "credentials.allowDevKey", &c.Credentials.AllowDevKey},
+
+		{"DB_USER", "database.user", &c.Database.User},
+		{"DB_PASSWORD", "database.password", &c.Database.Password},
+		{"DB_HOST", "database.host", &c.Database.Host},
+		{"DB_PORT", "database.port", &c.Database.Port},
+		{"DB_NAME", "database.name", &c.Database.Name},
+
+		{"LOG_LEVEL", "logging.level", &c.Logging.Level},
+		{"LOG_FORMAT", "logging.format", &c.Logging.Format},
+
+		{"APP_MODE", "cluster.mode", &c.Cluster.Mode},
+		{"NODE_ID", "cluster.nodeId", &c.Cluster.NodeID},
+		{"WORKER_CLAIM_INTERVAL", "cluster.claimInterval", &c.Cluster.ClaimInterval},
+
+		{"TRACING_EXPORTER", "tracing.exporter", &c.Tracing.Exporter},
+		{"OTEL_SERVICE_NAME", "tracing.serviceName", &c.Tracing.ServiceName},
+		{"TRACING_SAMPLE_RATIO", "tracing.sampleRatio", &c.Tracing.SampleRatio},
+
+		{"BROKER", "broker.kind", &c.Broker.Kind},
+		{"BROKER_POLL_INTERVAL", "broker.pollInterval", &c.Broker.PollInterval},
+		{"BROKER_RETENTION", "broker.retention", &c.Broker.Retention},
+
+		{"CRAWL_MAX_JOBS_PER_USER", "taskQueue.maxJobsPerUser", &c.TaskQueue.MaxJobsPerUser},
+		{"CRAWL_WORKERS", "taskQueue.workers", &c.TaskQueue.Workers},
+		{"CRAWL_MAX_WORKERS", "taskQueue.maxWorkers", &c.TaskQueue.MaxWorkers},
+		{"CRAWL_QUEUE_CAPACITY", "taskQueue.queueCapacity", &c.TaskQueue.QueueCapacity},
+
+		{"CRAWL_RETRY_MAX_ATTEMPTS", "retry.maxAttempts", &c.Retry.MaxAttempts},
+		{"CRAWL_RETRY_BASE_DELAY", "retry.baseDelay", &c.Retry.BaseDelay},
+		{"CRAWL_RETRY_MAX_DELAY", "retry.maxDelay", &c.Retry.MaxDelay},
+
+		{"CRAWL_PROXIES", "egress.proxies", &c.Egress.Proxies},
+		{"CRAWL_PROXY_MAX_FAILURES", "egress.maxConsecutiveFailures", &c.Egress.MaxConsecutiveFailures},
+		{"CRAWL_PROXY_COOLDOWN", "egress.failureCooldown", &c.Egress.FailureCooldown},
+		{"SSRF_ALLOWLIST", "egress.ssrfAllowlist", &c.Egress.SSRFAllowlist},
+
+		{"WEBHOOK_MAX_ATTEMPTS", "webhooks.maxAttempts", &c.Webhooks.MaxAttempts},
+		{"WEBHOOK_RETRY_DELAY", "webhooks.retryDelay", &c.Webhooks.RetryDelay},
+		{"WEBHOOK_TIMEOUT", "webhooks.timeout", &c.Webhooks.Timeout},
+		{"WEBHOOK_POLL_INTERVAL", "webhooks.pollInterval", &c.Webhooks.PollInterval},
+
+		{"JANITOR_INTERVAL", "janitor.interval", &c.Janitor.Interval},
+		{"JOB_RETENTION_DAYS", "janitor.retentionDays", &c.Janitor.RetentionDays},
+		{"JOB_RETENTION_MODE", "janitor.mode", &c.Janitor.Mode},
+		{"STALE_JOB_TIMEOUT", "janitor.staleTimeout", &c.Janitor.StaleTimeout},
+
+		{"EVENT_HISTORY_SIZE", "events.size", &c.Events.Size},
+	}
+}
+
+// loadEnv overrides values with the environment variables that are set
+func (c *Config) loadEnv() error {
+	var problems []string
+	for _, s := range c.settings() {
+		value, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+		if err := parseValue(strings.TrimSpace(value), s.target); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", s.env, err))
+		}
+	}
+	return newError(problems)
+}
+
+func parseValue(value string, target any) error {
+	switch target := target.(type) {
+	case *string:
+		*target = value
+	case *bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		*target = parsed
+	case *int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		*target = parsed
+	case *float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		*target = parsed
+	case *time.Duration:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s or 5m", value)
+		}
+		*target = parsed
+	case *[]string:
+		*target = splitList(value)
+	case encoding.TextUnmarshaler:
+		if err := target.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("%q is not valid: %v", value, err)
+		}
+	default:
+		return fmt.Errorf("unsupported setting type %T", target)
+	}
+	return nil
+}
+
+// splitList parses a comma separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
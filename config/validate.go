@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"sykell-challenge/backend/logging"
+	"sykell-challenge/backend/services/broker"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/services/janitor"
+	"sykell-challenge/backend/services/telemetry"
+	"sykell-challenge/backend/utils/egress"
+)
+
+// minJWTSecretLength is the minimum HS256 key size, 256 bits
+const minJWTSecretLength = 32
+
+// minEncryptionKeyLength keeps the passphrase of the AES-256 key from being guessable
+const minEncryptionKeyLength = 32
+
+// Error lists every problem found in the configuration
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+func newError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &Error{Problems: problems}
+}
+
+// validator collects problems, naming each value by its YAML key and environment variable
+type validator struct {
+	names    map[any]string
+	problems []string
+}
+
+func (v *validator) check(target any, ok bool, format string, args ...any) {
+	if !ok {
+		v.problems = append(v.problems, v.names[target]+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+// Validate reports all invalid values at once
+func (c *Config) Validate() error {
+	v := &validator{names: make(map[any]string)}
+	for _, s := range c.settings() {
+		v.names[s.target] = fmt.Sprintf("%s (%s)", s.key, s.env)
+	}
+
+	v.check(&c.Server.Port, c.Server.Port > 0 && c.Server.Port <= 65535, "must be a port between 1 and 65535")
+	v.check(&c.Server.AllowedOrigins, len(c.Server.AllowedOrigins) > 0 || c.Cluster.Mode == cluster.ModeWorker,
+		"is required, e.g. http://localhost:3000")
+	for _, origin := range c.Server.AllowedOrigins {
+		parsed, err := url.Parse(origin)
+		v.check(&c.Server.AllowedOrigins, err == nil && parsed.Scheme != "" && parsed.Host != "",
+			"%q is not an origin such as https://example.com", origin)
+	}
+	v.check(&c.Server.ShutdownTimeout, c.Server.ShutdownTimeout > 0, "must be positive")
+
+	v.check(&c.Auth.JWTSecret, len(c.Auth.JWTSecret) >= minJWTSecretLength,
+		"is required and must be at least %d characters", minJWTSecretLength)
+	v.check(&c.Auth.TokenTTL, c.Auth.TokenTTL > 0, "must be positive")
+	v.check(&c.Credentials.EncryptionKey, len(c.Credentials.EncryptionKey) >= minEncryptionKeyLength,
+		"is required and must be at least %d characters", minEncryptionKeyLength)
+	v.check(&c.Credentials.EncryptionKey, c.Credentials.EncryptionKey != DevEncryptionKey || c.Credentials.AllowDevKey,
+		"must not be the public development key unless credentials.allowDevKey (CREDENTIALS_ALLOW_DEV_KEY) is set")
+
+	v.check(&c.Database.Host, c.Database.Host != "", "is required")
+	v.check(&c.Database.Port, isPort(c.Database.Port), "must be a port between 1 and 65535")
+	v.check(&c.Database.User, c.Database.User != "", "is required")
+	v.check(&c.Database.Name, c.Database.Name != "", "is required")
+
+	v.check(&c.Logging.Format, c.Logging.Format == logging.FormatJSON || c.Logging.Format == logging.FormatText,
+		"must be %s or %s", logging.FormatJSON, logging.FormatText)
+
+	v.check(&c.Cluster.Mode, c.Cluster.Mode == cluster.ModeAll || c.Cluster.Mode == cluster.ModeAPI || c.Cluster.Mode == cluster.ModeWorker,
+		"must be %s, %s or %s", cluster.ModeAll, cluster.ModeAPI, cluster.ModeWorker)
+	v.check(&c.Cluster.NodeID, c.Cluster.NodeID != "", "is required")
+	v.check(&c.Cluster.ClaimInterval, c.Cluster.ClaimInterval > 0, "must be positive")
+
+	v.check(&c.Tracing.Exporter, c.Tracing.Exporter == telemetry.ExporterNone || c.Tracing.Exporter == telemetry.ExporterOTLP,
+		"must be %s or %s", telemetry.ExporterNone, telemetry.ExporterOTLP)
+	v.check(&c.Tracing.ServiceName, c.Tracing.ServiceName != "", "is required")
+	v.check(&c.Tracing.SampleRatio, c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "must be between 0 and 1")
+
+	v.check(&c.Broker.Kind, c.Broker.Kind == broker.KindLocal || c.Broker.Kind == broker.KindDatabase,
+		"must be %s or %s", broker.KindLocal, broker.KindDatabase)
+	v.check(&c.Broker.PollInterval, c.Broker.PollInterval > 0, "must be positive")
+	v.check(&c.Broker.Retention, c.Broker.Retention > 0, "must be positive")
+
+	v.check(&c.TaskQueue.MaxJobsPerUser, c.TaskQueue.MaxJobsPerUser >= 0, "must not be negative")
+	v.check(&c.TaskQueue.Workers, c.TaskQueue.Workers >= 1, "must be at least 1")
+	v.check(&c.TaskQueue.MaxWorkers, c.TaskQueue.MaxWorkers >= c.TaskQueue.Workers, "must be at least the worker count (%d)", c.TaskQueue.Workers)
+	v.check(&c.TaskQueue.QueueCapacity, c.TaskQueue.QueueCapacity >= 0, "must not be negative")
+
+	v.check(&c.Retry.MaxAttempts, c.Retry.MaxAttempts >= 1, "must be at least 1")
+	v.check(&c.Retry.BaseDelay, c.Retry.BaseDelay > 0, "must be positive")
+	v.check(&c.Retry.MaxDelay, c.Retry.MaxDelay >= c.Retry.BaseDelay, "must be at least the base delay (%s)", c.Retry.BaseDelay)
+
+	for _, proxy := range c.Egress.Proxies {
+		_, err := egress.ParseProxyURL(proxy)
+		v.check(&c.Egress.Proxies, err == nil, "%v", err)
+	}
+	v.check(&c.Egress.MaxConsecutiveFailures, c.Egress.MaxConsecutiveFailures >= 1, "must be at least 1")
+	v.check(&c.Egress.FailureCooldown, c.Egress.FailureCooldown >= 0, "must not be negative")
+
+	v.check(&c.Webhooks.MaxAttempts, c.Webhooks.MaxAttempts >= 1, "must be at least 1")
+	v.check(&c.Webhooks.RetryDelay, c.Webhooks.RetryDelay > 0, "must be positive")
+	v.check(&c.Webhooks.Timeout, c.Webhooks.Timeout > 0, "must be positive")
+	v.check(&c.Webhooks.PollInterval, c.Webhooks.PollInterval > 0, "must be positive")
+
+	v.check(&c.Janitor.Interval, c.Janitor.Interval > 0, "must be positive")
+	v.check(&c.Janitor.RetentionDays, c.Janitor.RetentionDays >= 0, "must not be negative")
+	v.check(&c.Janitor.Mode, c.Janitor.Mode == janitor.ModeArchive || c.Janitor.Mode == janitor.ModeDelete,
+		"must be %s or %s", janitor.ModeArchive, janitor.ModeDelete)
+	v.check(&c.Janitor.StaleTimeout, c.Janitor.StaleTimeout > 0, "must be positive")
+	// Each pass refreshes the node's live jobs, so passes must come more often than the timeout
+	v.check(&c.Janitor.StaleTimeout, c.Janitor.StaleTimeout <= 0 || c.Janitor.StaleTimeout > c.Janitor.Interval,
+		"must be longer than the janitor interval (%s)", c.Janitor.Interval)
+
+	v.check(&c.Events.Size, c.Events.Size >= 1, "must be at least 1")
+
+	return newError(v.problems)
+}
+
+func isPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port > 0 && port <= 65535
+}
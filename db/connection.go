@@ -2,39 +2,40 @@ package db
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-
-	"sykell-challenge/backend/utils"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
 var (
 	instance *gorm.DB
 	once     sync.Once
 	initErr  error
+	config   = DefaultConfig()
 )
 
 // Config holds database configuration
 type Config struct {
-	User     string
-	Password string
-	Host     string
-	Port     string
-	Name     string
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Name     string `yaml:"name"`
 }
 
-// LoadConfig loads database configuration from environment variables
-func LoadConfig() *Config {
+// DefaultConfig returns the default database configuration for local development
+func DefaultConfig() *Config {
 	return &Config{
-		User:     utils.GetEnv("DB_USER", "sykell"),
-		Password: utils.GetEnv("DB_PASSWORD", "sykellpass"),
-		Host:     utils.GetEnv("DB_HOST", "127.0.0.1"),
-		Port:     utils.GetEnv("DB_PORT", "3306"),
-		Name:     utils.GetEnv("DB_NAME", "websites_dev"),
+		User:     "sykell",
+		Password: "sykellpass",
+		Host:     "127.0.0.1",
+		Port:     "3306",
+		Name:     "websites_dev",
 	}
 }
 
@@ -52,13 +53,18 @@ func Connect(config *Config) (*gorm.DB, error) {
 			NowFunc: func() time.Time {
 				return time.Now().Local()
 			},
+			Logger: newLogger(),
 		})
 		if err == nil {
-			log.Printf("Successfully connected to database on attempt %d", i+1)
+			slog.Info("Connected to database", "attempt", i+1)
+			// Trace queries without their parameters, which may hold credentials
+			if err := db.Use(tracing.NewPlugin(tracing.WithoutMetrics(), tracing.WithoutQueryVariables())); err != nil {
+				slog.Warn("Failed to enable query tracing", "error", err)
+			}
 			return db, nil
 		}
 
-		log.Printf("Failed to connect to database (attempt %d/%d): %v", i+1, maxRetries, err)
+		slog.Warn("Failed to connect to database", "attempt", i+1, "max_attempts", maxRetries, "error", err)
 		if i < maxRetries-1 {
 			time.Sleep(retryDelay)
 		}
@@ -67,10 +73,25 @@ func Connect(config *Config) (*gorm.DB, error) {
 	return nil, fmt.Errorf("failed to connect database after %d attempts", maxRetries)
 }
 
+// newLogger writes slow queries and errors through the application logger.
+// Query parameters are left out since they may hold credentials.
+func newLogger() logger.Interface {
+	return logger.New(slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn), logger.Config{
+		SlowThreshold:             200 * time.Millisecond,
+		LogLevel:                  logger.Warn,
+		IgnoreRecordNotFoundError: true,
+		ParameterizedQueries:      true,
+	})
+}
+
+// Configure sets the connection settings; call it before the first GetDB
+func Configure(dbConfig *Config) {
+	config = dbConfig
+}
+
 // GetDB returns the database instance, initializing it if necessary
 func GetDB() *gorm.DB {
 	once.Do(func() {
-		config := LoadConfig()
 		instance, initErr = Connect(config)
 	})
 
@@ -1,7 +1,7 @@
 package db
 
 import (
-	"log"
+	"log/slog"
 )
 
 // Init initializes the database connection and runs migrations
@@ -12,9 +12,9 @@ func Init() {
 
 	// Run migrations
 	if err := MigrateAll(); err != nil {
-		log.Printf("Migration failed: %v", err)
+		slog.Error("Migration failed", "error", err)
 		panic("failed to migrate database")
 	}
 
-	log.Println("Database initialized successfully")
+	slog.Info("Database initialized successfully")
 }
@@ -1,15 +1,33 @@
 package db
 
 import (
+	"sync/atomic"
+
 	"sykell-challenge/backend/models"
 )
 
+// migrated is set once the schema is up to date
+var migrated atomic.Bool
+
 // MigrateAll runs auto-migration for all models
 func MigrateAll() error {
 	db := GetDB()
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&models.URL{},
 		&models.User{},
 		&models.CrawlJob{},
+		&models.Credential{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.BrokerMessage{},
 	)
+	if err == nil {
+		migrated.Store(true)
+	}
+	return err
+}
+
+// Migrated reports whether MigrateAll has completed successfully
+func Migrated() bool {
+	return migrated.Load()
 }
@@ -0,0 +1,17 @@
+package admin
+
+import (
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/utils/egress"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /admin/proxies - Health of the outbound proxies used by this server
+func (h *AdminHandler) GetProxies(c *gin.Context) {
+	helpers.SendSuccessResponse(c, gin.H{
+		"node": cluster.NodeID(),
+		"data": egress.Stats(),
+	})
+}
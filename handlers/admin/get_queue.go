@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/services/taskq"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /admin/queue - Worker pool and queue state of this server
+func (h *AdminHandler) GetQueue(c *gin.Context) {
+	helpers.SendSuccessResponse(c, gin.H{
+		"node": cluster.NodeID(),
+		"data": taskq.Stats(),
+		"jobs": queuedJobs(),
+	})
+}
+
+func queuedJobs() []taskq.QueuedJob {
+	jobs := taskq.QueuedJobs()
+	if jobs == nil {
+		return []taskq.QueuedJob{}
+	}
+	return jobs
+}
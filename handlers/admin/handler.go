@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	userRepo *repositories.UserRepository
+}
+
+func NewAdminHandler() *AdminHandler {
+	db := db.GetDB()
+	return &AdminHandler{
+		userRepo: repositories.NewUserRepository(db),
+	}
+}
+
+// RequireAdmin only lets active admin users through; use it after auth.JWTMiddleware
+func (h *AdminHandler) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := auth.GetCurrentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		// Checked on every request, so revoking admin rights takes effect immediately
+		user, err := h.userRepo.GetByID(userID)
+		if err != nil || !user.IsActive || !user.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
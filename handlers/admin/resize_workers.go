@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"errors"
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/services/taskq"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResizeWorkersRequest struct {
+	Workers int `json:"workers" binding:"required,min=1"`
+}
+
+// PUT /admin/queue/workers - Change the number of concurrent crawls on every worker node
+func (h *AdminHandler) ResizeWorkers(c *gin.Context) {
+	var request ResizeWorkersRequest
+	if !helpers.ValidateJSONBinding(c, &request) {
+		return
+	}
+
+	// Applied here first so that invalid counts are reported to the caller
+	if taskq.Started() {
+		if err := taskq.Resize(request.Workers); err != nil {
+			if errors.Is(err, taskq.ErrInvalidWorkerCount) {
+				helpers.SendBadRequestError(c, err.Error())
+				return
+			}
+			helpers.SendInternalError(c, err.Error())
+			return
+		}
+	}
+
+	if err := cluster.ResizeWorkers(request.Workers); err != nil {
+		helpers.SendInternalError(c, "Failed to signal the worker nodes")
+		return
+	}
+
+	helpers.SendSuccessResponse(c, gin.H{
+		"message": "Worker count updated",
+		"data":    taskq.Stats(),
+	})
+}
@@ -1,10 +1,11 @@
 package crawl
 
 import (
-	"log"
 	"net/http"
-	"sykell-challenge/backend/services/socket"
-	"sykell-challenge/backend/services/taskq"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/logging"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/utils/crawl/crawl_manager"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,8 +25,14 @@ func (h *CrawlHandler) HandleCancelCrawl(g *gin.Context) {
 		return
 	}
 
+	userID, _ := auth.GetCurrentUserID(g)
+	if jobRecord.UserID != 0 && jobRecord.UserID != userID {
+		g.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter can cancel this job"})
+		return
+	}
+
 	// Check if the job is cancellable
-	if jobRecord.Status == "done" {
+	if jobRecord.Status == "completed" || jobRecord.Status == "truncated" || jobRecord.Status == "timeout" {
 		g.JSON(http.StatusConflict, gin.H{"error": "Job already completed"})
 		return
 	}
@@ -40,14 +47,11 @@ func (h *CrawlHandler) HandleCancelCrawl(g *gin.Context) {
 		return
 	}
 
-	// Try to cancel the running job
-	if jobRecord.Status == "running" {
-		if taskq.CancelJob(jobID) {
-			// Job was running and successfully cancelled
-			log.Printf("Cancelled running job: %s", jobID)
-		} else {
-			// Job might have just finished or wasn't found in running jobs
-			log.Printf("Job not found in running jobs, updating status anyway: %s", jobID)
+	// Drop the job from the queue or stop it, on whichever node holds it
+	if jobRecord.Status == "queued" || jobRecord.Status == "running" {
+		if err := cluster.CancelJob(jobID); err != nil {
+			// The status change below still stops the job before it starts or saves results
+			logging.FromGin(g).Warn("Failed to signal cancellation of job", "job_id", jobID, "error", err)
 		}
 	}
 
@@ -63,17 +67,12 @@ func (h *CrawlHandler) HandleCancelCrawl(g *gin.Context) {
 	}
 
 	// Broadcast cancellation
-	socket.BroadcastCrawlUpdate("crawl_cancelled", map[string]interface{}{
-		"jobId":  jobID,
-		"url":    jobRecord.URL,
-		"url_id": jobRecord.ID,
-		"status": "cancelled",
-	})
+	crawl_manager.BroadcastCancelled(*jobRecord)
 
 	g.JSON(http.StatusOK, gin.H{
 		"message": "Job cancelled successfully",
 		"job_id":  jobID,
-		"url_id":  jobRecord.ID,
+		"url_id":  jobRecord.URLID,
 		"status":  "cancelled",
 	})
 }
@@ -1,20 +1,24 @@
 package crawl
 
 import (
-	"context"
 	"fmt"
-	"log"
 	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/logging"
 	"sykell-challenge/backend/models"
 	"sykell-challenge/backend/services/crawl"
-	"sykell-challenge/backend/services/taskq"
 	"sykell-challenge/backend/utils"
+	"sykell-challenge/backend/utils/crawl/crawl_manager"
+	"sykell-challenge/backend/utils/egress"
+	"sykell-challenge/backend/validators"
 
 	"github.com/gin-gonic/gin"
 )
 
 type CrawlRequest struct {
-	URL string `json:"url" binding:"required"`
+	URL      string               `json:"url" binding:"required"`
+	Priority string               `json:"priority" binding:"omitempty,oneof=low normal high"`
+	Options  *models.CrawlOptions `json:"options"`
 }
 
 func (h *CrawlHandler) HandleCrawlURL(g *gin.Context) {
@@ -22,13 +26,43 @@ func (h *CrawlHandler) HandleCrawlURL(g *gin.Context) {
 	if err := h.validateRequeest(g, &request); err != nil {
 		return
 	}
-	log.Printf("request url: %v", request)
+	logging.FromGin(g).Debug("Crawl requested", "url", request.URL)
+
+	var options models.CrawlOptions
+	if request.Options != nil {
+		options = *request.Options
+	}
 
 	if crawled := h.isUrlAlreadyCrawled(g, request.URL); crawled {
 		return
 	}
 
-	if isUrlAvailable, statusCode := utils.IsURLAvailable(request.URL); !isUrlAvailable {
+	if !h.checkTargetAllowed(g, request.URL) {
+		return
+	}
+	if options.Login != nil && !h.checkTargetAllowed(g, options.Login.FormURL) {
+		return
+	}
+
+	transport, err := crawl_manager.NewTransport(options)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pingOptions := crawl_manager.NewPingOptions(options, true)
+	pingOptions.Transport = transport
+	if result := utils.PingURLContext(g.Request.Context(), request.URL, pingOptions); !result.Available {
+		if egress.IsBlocked(result.Err) {
+			// The URL redirected to a forbidden address
+			g.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"message": result.Err.Error(), "code": http.StatusBadRequest}})
+			return
+		}
+
+		statusCode := result.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusBadGateway
+		}
 		g.JSON(statusCode, gin.H{"error": gin.H{"message": "URL is not available", "code": statusCode}})
 		return
 	}
@@ -47,7 +81,12 @@ func (h *CrawlHandler) HandleCrawlURL(g *gin.Context) {
 		return
 	}
 
-	crawlTask := crawl.CreateCrawlTask(request.URL, newURL.ID)
+	userID, _ := auth.GetCurrentUserID(g)
+	priority := request.Priority
+	if priority == "" {
+		priority = models.PriorityNormal
+	}
+	crawlTask := crawl.CreateCrawlTask(g.Request.Context(), request.URL, newURL.ID, userID, priority, options)
 
 	// update url in database with jobid
 	newURL.JobId = fmt.Sprintf("%d", crawlTask.CrawlJob.ID)
@@ -60,14 +99,10 @@ func (h *CrawlHandler) HandleCrawlURL(g *gin.Context) {
 	}
 
 	// start crawling in background
-	ctx := context.Background()
-	_, err := taskq.EnqueueTask(ctx, crawlTask)
-	if err != nil {
+	if err := crawl.Submit(crawlTask); err != nil {
 		h.urlRepo.UpdateStatus(newURL.ID, "error")
-		g.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{
-			"message": "Failed to enqueue crawl task",
-			"code":    http.StatusInternalServerError,
-		}})
+		h.jobRepo.MarkFailed(newURL.JobId, err.Error())
+		sendEnqueueError(g, err)
 		return
 	}
 
@@ -85,9 +120,45 @@ func (h *CrawlHandler) validateRequeest(g *gin.Context, request *CrawlRequest) e
 		return err
 	}
 
+	if request.Options != nil {
+		if err := validators.ValidateCrawlOptions(*request.Options); err != nil {
+			g.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return err
+		}
+
+		if login := request.Options.Login; login != nil {
+			userID, _ := auth.GetCurrentUserID(g)
+			if _, err := h.credentialRepo.GetByIDForUser(login.CredentialID, userID); err != nil {
+				g.JSON(http.StatusBadRequest, gin.H{"error": "Login credential not found"})
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// checkTargetAllowed rejects URLs that resolve to private, loopback or metadata addresses
+func (h *CrawlHandler) checkTargetAllowed(g *gin.Context, urlString string) bool {
+	err := egress.DefaultGuard().CheckURL(g.Request.Context(), urlString)
+	if err == nil {
+		return true
+	}
+
+	if egress.IsBlocked(err) {
+		g.JSON(http.StatusBadRequest, gin.H{"error": gin.H{
+			"message": "URL targets a forbidden address: " + err.Error(),
+			"code":    http.StatusBadRequest,
+		}})
+	} else {
+		g.JSON(http.StatusBadRequest, gin.H{"error": gin.H{
+			"message": "URL could not be resolved: " + err.Error(),
+			"code":    http.StatusBadRequest,
+		}})
+	}
+	return false
+}
+
 func (h *CrawlHandler) isUrlAlreadyCrawled(g *gin.Context, urlString string) bool {
 
 	existingURL, err := h.urlRepo.GetByURL(urlString)
@@ -0,0 +1,22 @@
+package crawl
+
+import (
+	"errors"
+	"net/http"
+	"sykell-challenge/backend/services/taskq"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sendEnqueueError reports a failed crawl.Submit; a full queue is temporary
+func sendEnqueueError(g *gin.Context, err error) {
+	status, message := http.StatusInternalServerError, "Failed to enqueue crawl task"
+	if errors.Is(err, taskq.ErrQueueFull) {
+		status, message = http.StatusServiceUnavailable, "Crawl queue is full, try again later"
+	}
+
+	g.JSON(status, gin.H{"error": gin.H{
+		"message": message,
+		"code":    status,
+	}})
+}
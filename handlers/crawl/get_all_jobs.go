@@ -2,20 +2,41 @@ package crawl
 
 import (
 	"net/http"
+	"sykell-challenge/backend/auth"
 	"sykell-challenge/backend/repositories"
 
 	"github.com/gin-gonic/gin"
 )
 
-// HandleGetAllCrawlJobs returns all past crawl jobs
+// HandleGetAllCrawlJobs returns past crawl jobs with pagination, filters and statistics
 func (h *CrawlHandler) HandleGetAllCrawlJobs(c *gin.Context) {
-	repo := repositories.NewCrawlJobRepository(h.db)
+	var params repositories.CrawlJobQueryParams
 
-	jobs, err := repo.GetJobHistory()
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 || params.Limit > 100 {
+		params.Limit = 10
+	}
+	if params.From != nil && params.To != nil && params.To.Before(*params.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'to' must not be before 'from'"})
+		return
+	}
+
+	// Users only see their own jobs, whose options may hold their cookies and headers
+	userID, _ := auth.GetCurrentUserID(c)
+	params.UserID = &userID
+
+	result, err := h.jobRepo.GetJobsWithParams(params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, jobs)
+	c.JSON(http.StatusOK, result)
 }
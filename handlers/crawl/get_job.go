@@ -0,0 +1,58 @@
+package crawl
+
+import (
+	"fmt"
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/services/taskq"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CrawlJobDetail is a crawl job with its duration and the state of its URL
+type CrawlJobDetail struct {
+	models.CrawlJob
+	DurationSeconds *float64 `json:"durationSeconds"` // Set once the job has finished
+	URLStatus       string   `json:"urlStatus,omitempty"`
+	IsCurrentJob    bool     `json:"isCurrentJob"`            // Whether the URL's stored results come from this job
+	QueuePosition   *int     `json:"queuePosition,omitempty"` // 1-based, while the job is waiting
+}
+
+// HandleGetCrawlJob returns a single crawl job with its progress counters and duration
+func (h *CrawlHandler) HandleGetCrawlJob(g *gin.Context) {
+	id, ok := helpers.ParseIDParam(g, "jobId")
+	if !ok {
+		return
+	}
+
+	jobRecord, err := h.jobRepo.GetByID(fmt.Sprint(id))
+	if helpers.HandleDBError(g, err, "Job not found") {
+		return
+	}
+
+	// Options and checkpoints may hold the submitter's cookies, headers and login details
+	userID, _ := auth.GetCurrentUserID(g)
+	if jobRecord.UserID != 0 && jobRecord.UserID != userID {
+		g.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter can view this job"})
+		return
+	}
+
+	detail := CrawlJobDetail{CrawlJob: *jobRecord}
+	if jobRecord.StartedAt != nil && jobRecord.CompletedAt != nil {
+		duration := jobRecord.CompletedAt.Sub(*jobRecord.StartedAt).Seconds()
+		detail.DurationSeconds = &duration
+	}
+
+	if urlRecord, err := h.urlRepo.GetByID(jobRecord.URLID); err == nil {
+		detail.URLStatus = urlRecord.Status
+		detail.IsCurrentJob = urlRecord.JobId == fmt.Sprint(jobRecord.ID)
+	}
+
+	if position, queued := taskq.QueuePosition(fmt.Sprint(jobRecord.ID)); queued {
+		detail.QueuePosition = &position
+	}
+
+	g.JSON(http.StatusOK, detail)
+}
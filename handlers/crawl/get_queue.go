@@ -0,0 +1,21 @@
+package crawl
+
+import (
+	"net/http"
+	"sykell-challenge/backend/services/taskq"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetQueue lists waiting jobs with their queue positions
+func (h *CrawlHandler) HandleGetQueue(g *gin.Context) {
+	jobs := taskq.QueuedJobs()
+	if jobs == nil {
+		jobs = []taskq.QueuedJob{}
+	}
+
+	g.JSON(http.StatusOK, gin.H{
+		"data":  jobs,
+		"total": len(jobs),
+	})
+}
@@ -11,14 +11,16 @@ func NewCrawlHandler() *CrawlHandler {
 	db := db.GetDB()
 
 	return &CrawlHandler{
-		db:      db,
-		urlRepo: repositories.NewURLRepository(db),
-		jobRepo: repositories.NewCrawlJobRepository(db),
+		db:             db,
+		urlRepo:        repositories.NewURLRepository(db),
+		jobRepo:        repositories.NewCrawlJobRepository(db),
+		credentialRepo: repositories.NewCredentialRepository(db),
 	}
 }
 
 type CrawlHandler struct {
-	db      *gorm.DB
-	urlRepo *repositories.URLRepository
-	jobRepo *repositories.CrawlJobRepository
+	db             *gorm.DB
+	urlRepo        *repositories.URLRepository
+	jobRepo        *repositories.CrawlJobRepository
+	credentialRepo *repositories.CredentialRepository
 }
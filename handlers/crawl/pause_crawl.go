@@ -0,0 +1,76 @@
+package crawl
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/logging"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/utils/crawl/crawl_manager"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlePauseCrawl pauses a queued or running job. A running job stops
+// asynchronously and saves a checkpoint to resume from.
+func (h *CrawlHandler) HandlePauseCrawl(g *gin.Context) {
+	jobID := g.Param("jobId")
+
+	jobRecord, err := h.jobRepo.GetByID(jobID)
+	if err != nil {
+		g.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	userID, _ := auth.GetCurrentUserID(g)
+	if jobRecord.UserID != 0 && jobRecord.UserID != userID {
+		g.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter can pause this job"})
+		return
+	}
+
+	switch jobRecord.Status {
+	case "queued":
+		// Marked paused first, so a worker that is about to start it skips it
+		paused, err := h.jobRepo.TransitionStatus(jobID, "queued", "paused")
+		if err != nil {
+			g.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job status"})
+			return
+		}
+		if !paused {
+			g.JSON(http.StatusConflict, gin.H{"error": "Job started or changed meanwhile, try again"})
+			return
+		}
+		h.urlRepo.UpdateStatus(jobRecord.URLID, "paused")
+
+		if err := cluster.PauseJob(jobID); err != nil {
+			logging.FromGin(g).Warn("Failed to remove paused job from the queue", "job_id", jobID, "error", err)
+		}
+
+		jobRecord.Status = "paused"
+		crawl_manager.BroadcastPaused(*jobRecord)
+
+		g.JSON(http.StatusOK, gin.H{
+			"message": "Job paused",
+			"jobId":   jobID,
+			"status":  "paused",
+		})
+
+	case "running":
+		if err := cluster.PauseJob(jobID); err != nil {
+			g.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to signal the worker running the job"})
+			return
+		}
+
+		// The task saves its checkpoint and broadcasts crawl_paused once it stopped
+		g.JSON(http.StatusAccepted, gin.H{
+			"message": "Pause requested",
+			"jobId":   jobID,
+			"status":  "pausing",
+		})
+
+	case "paused":
+		g.JSON(http.StatusConflict, gin.H{"error": "Job already paused"})
+
+	default:
+		g.JSON(http.StatusConflict, gin.H{"error": "Only queued or running jobs can be paused"})
+	}
+}
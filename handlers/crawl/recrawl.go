@@ -0,0 +1,68 @@
+package crawl
+
+import (
+	"fmt"
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/services/crawl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleRecrawl starts a new crawl of a job's URL reusing the job's crawl options
+func (h *CrawlHandler) HandleRecrawl(g *gin.Context) {
+	jobID := g.Param("jobId")
+
+	jobRecord, err := h.jobRepo.GetByID(jobID)
+	if err != nil {
+		g.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	// The job's options may carry the submitter's cookies, passwords and credentials
+	userID, _ := auth.GetCurrentUserID(g)
+	if jobRecord.UserID != 0 && jobRecord.UserID != userID {
+		g.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter can recrawl this job"})
+		return
+	}
+
+	if jobRecord.Status == "queued" || jobRecord.Status == "running" {
+		g.JSON(http.StatusConflict, gin.H{"error": "Job is still in progress"})
+		return
+	}
+
+	if jobRecord.Status == "paused" {
+		g.JSON(http.StatusConflict, gin.H{"error": "Job is paused, resume it instead"})
+		return
+	}
+
+	urlRecord, err := h.urlRepo.GetByID(jobRecord.URLID)
+	if err != nil {
+		g.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+		return
+	}
+
+	crawlTask := crawl.CreateCrawlTask(g.Request.Context(), urlRecord.URL, urlRecord.ID, userID, jobRecord.Priority, jobRecord.Options)
+
+	urlRecord.JobId = fmt.Sprintf("%d", crawlTask.CrawlJob.ID)
+	urlRecord.Status = "queued"
+	if err := h.urlRepo.Update(urlRecord); err != nil {
+		g.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{
+			"message": "Failed to update URL with job ID",
+			"code":    http.StatusInternalServerError,
+		}})
+		return
+	}
+
+	if err := crawl.Submit(crawlTask); err != nil {
+		h.urlRepo.UpdateStatus(urlRecord.ID, "error")
+		h.jobRepo.MarkFailed(urlRecord.JobId, err.Error())
+		sendEnqueueError(g, err)
+		return
+	}
+
+	g.JSON(http.StatusCreated, gin.H{
+		"data":  urlRecord,
+		"jobId": crawlTask.CrawlJob.ID,
+	})
+}
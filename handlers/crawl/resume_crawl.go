@@ -0,0 +1,52 @@
+package crawl
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/services/crawl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleResumeCrawl requeues a paused job, which continues from its checkpoint
+func (h *CrawlHandler) HandleResumeCrawl(g *gin.Context) {
+	jobID := g.Param("jobId")
+
+	jobRecord, err := h.jobRepo.GetByID(jobID)
+	if err != nil {
+		g.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	userID, _ := auth.GetCurrentUserID(g)
+	if jobRecord.UserID != 0 && jobRecord.UserID != userID {
+		g.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter can resume this job"})
+		return
+	}
+
+	if jobRecord.Status != "paused" {
+		g.JSON(http.StatusConflict, gin.H{"error": "Only paused jobs can be resumed"})
+		return
+	}
+
+	if err := h.urlRepo.UpdateStatus(jobRecord.URLID, "queued"); err != nil {
+		g.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update URL status"})
+		return
+	}
+
+	crawlTask := crawl.ResumeCrawlTask(g.Request.Context(), *jobRecord)
+
+	if err := crawl.Submit(crawlTask); err != nil {
+		h.jobRepo.UpdateStatus(jobID, "paused")
+		h.urlRepo.UpdateStatus(jobRecord.URLID, "paused")
+		sendEnqueueError(g, err)
+		return
+	}
+
+	g.JSON(http.StatusOK, gin.H{
+		"message":        "Job resumed",
+		"jobId":          jobID,
+		"status":         "queued",
+		"fromCheckpoint": jobRecord.Checkpoint != nil,
+	})
+}
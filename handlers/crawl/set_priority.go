@@ -0,0 +1,63 @@
+package crawl
+
+import (
+	"fmt"
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/logging"
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/services/taskq"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriorityRequest struct {
+	Priority string `json:"priority" binding:"required,oneof=low normal high"`
+}
+
+// HandleSetPriority changes the priority of a queued job
+func (h *CrawlHandler) HandleSetPriority(g *gin.Context) {
+	id, ok := helpers.ParseIDParam(g, "jobId")
+	if !ok {
+		return
+	}
+	jobID := fmt.Sprint(id)
+
+	var request PriorityRequest
+	if !helpers.ValidateJSONBinding(g, &request) {
+		return
+	}
+
+	jobRecord, err := h.jobRepo.GetByID(jobID)
+	if helpers.HandleDBError(g, err, "Job not found") {
+		return
+	}
+
+	userID, _ := auth.GetCurrentUserID(g)
+	if jobRecord.UserID != 0 && jobRecord.UserID != userID {
+		g.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter can reprioritize this job"})
+		return
+	}
+
+	if jobRecord.Status != "queued" {
+		helpers.SendConflictError(g, "Only queued jobs can be reprioritized")
+		return
+	}
+
+	if err := h.jobRepo.UpdatePriority(jobID, request.Priority); err != nil {
+		helpers.SendInternalError(g, "Failed to update job priority")
+		return
+	}
+	if err := cluster.SetJobPriority(jobID, models.PriorityRank(request.Priority)); err != nil {
+		logging.FromGin(g).Warn("Failed to reorder job", "job_id", jobID, "error", err)
+	}
+
+	position, _ := taskq.QueuePosition(jobID)
+	g.JSON(http.StatusOK, gin.H{
+		"jobId":         jobID,
+		"priority":      request.Priority,
+		"queuePosition": position,
+	})
+}
@@ -0,0 +1,107 @@
+package crawl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/services/events"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive is how often an idle stream sends a comment so proxies keep it open
+const streamKeepAlive = 15 * time.Second
+
+// streamResetEvent tells a client that events were lost and it should reload its state
+const streamResetEvent = "stream_reset"
+
+// HandleStreamEvents streams the current user's crawl events as Server-Sent Events
+func (h *CrawlHandler) HandleStreamEvents(g *gin.Context) {
+	userID, _ := auth.GetCurrentUserID(g)
+
+	streamEvents(g, func(event events.Event) bool {
+		return event.UserID == userID
+	})
+}
+
+// streamEvents writes matching events to the client until it disconnects.
+// Each event carries its sequence number as the SSE id, so a reconnecting
+// client sending Last-Event-ID first receives what it missed.
+func streamEvents(g *gin.Context, match func(events.Event) bool) {
+	history := events.GetHistory()
+	if history == nil {
+		g.JSON(http.StatusServiceUnavailable, gin.H{"error": "Event stream is not available"})
+		return
+	}
+
+	lastID := g.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = g.Query("lastEventId")
+	}
+
+	seq := history.LastSeq()
+	if lastID != "" {
+		var err error
+		if seq, err = strconv.ParseUint(lastID, 10, 64); err != nil {
+			g.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+	}
+
+	missed, complete, records, stop := history.Listen(seq)
+	defer stop()
+
+	g.Header("Content-Type", "text/event-stream")
+	g.Header("Cache-Control", "no-cache")
+	g.Header("Connection", "keep-alive")
+	g.Header("X-Accel-Buffering", "no")
+	g.Status(http.StatusOK)
+
+	if !complete {
+		fmt.Fprintf(g.Writer, "event: %s\ndata: {}\n\n", streamResetEvent)
+	}
+	for _, record := range missed {
+		if match(record.Event) {
+			writeStreamEvent(g, record)
+		}
+	}
+	g.Writer.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-g.Request.Context().Done():
+			return
+		case record, ok := <-records:
+			if !ok {
+				// Fell behind or shutting down; the client reconnects with its last id
+				return
+			}
+			if match(record.Event) {
+				writeStreamEvent(g, record)
+				g.Writer.Flush()
+			}
+		case <-keepAlive.C:
+			fmt.Fprint(g.Writer, ": keep-alive\n\n")
+			g.Writer.Flush()
+		}
+	}
+}
+
+func writeStreamEvent(g *gin.Context, record events.Record) {
+	if record.Type == events.CrawlProgress {
+		// Too chatty for plain HTTP consumers; GET /crawl/:jobId has the counts
+		return
+	}
+
+	data, err := json.Marshal(record.Payload)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(g.Writer, "id: %d\nevent: %s\ndata: %s\n\n", record.Seq, record.Type, data)
+}
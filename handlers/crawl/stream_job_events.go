@@ -0,0 +1,35 @@
+package crawl
+
+import (
+	"fmt"
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/services/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleStreamJobEvents streams the events of a single job as Server-Sent Events
+func (h *CrawlHandler) HandleStreamJobEvents(g *gin.Context) {
+	id, ok := helpers.ParseIDParam(g, "jobId")
+	if !ok {
+		return
+	}
+	jobID := fmt.Sprint(id)
+
+	jobRecord, err := h.jobRepo.GetByID(jobID)
+	if helpers.HandleDBError(g, err, "Job not found") {
+		return
+	}
+
+	userID, _ := auth.GetCurrentUserID(g)
+	if jobRecord.UserID != 0 && jobRecord.UserID != userID {
+		g.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter can follow this job"})
+		return
+	}
+
+	streamEvents(g, func(event events.Event) bool {
+		return event.Payload.JobID == jobID
+	})
+}
@@ -0,0 +1,38 @@
+package credential
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /credentials - Store a credential for crawl login steps
+func (h *CredentialHandler) CreateCredential(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.CredentialCreateRequest
+	if !helpers.ValidateJSONBinding(c, &req) {
+		return
+	}
+
+	credential := models.Credential{
+		UserID:   userID,
+		Name:     req.Name,
+		Username: req.Username,
+		Password: req.Password,
+	}
+
+	if err := h.credentialRepo.Create(&credential); err != nil {
+		helpers.SendInternalError(c, "Failed to store credential")
+		return
+	}
+
+	helpers.SendCreatedResponse(c, gin.H{"data": credential})
+}
@@ -0,0 +1,29 @@
+package credential
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DELETE /credentials/:id - Delete one of the current user's credentials
+func (h *CredentialHandler) DeleteCredential(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, ok := helpers.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if helpers.HandleDBError(c, h.credentialRepo.Delete(id, userID), "Credential not found") {
+		return
+	}
+
+	helpers.SendSuccessResponse(c, gin.H{"message": "Credential deleted successfully"})
+}
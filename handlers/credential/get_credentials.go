@@ -0,0 +1,26 @@
+package credential
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /credentials - List the current user's credentials (passwords are never returned)
+func (h *CredentialHandler) GetCredentials(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	credentials, err := h.credentialRepo.GetByUserID(userID)
+	if err != nil {
+		helpers.SendInternalError(c, "Failed to fetch credentials")
+		return
+	}
+
+	helpers.SendSuccessResponse(c, gin.H{"data": credentials})
+}
@@ -0,0 +1,17 @@
+package credential
+
+import (
+	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/repositories"
+)
+
+type CredentialHandler struct {
+	credentialRepo *repositories.CredentialRepository
+}
+
+func NewCredentialHandler() *CredentialHandler {
+	db := db.GetDB()
+	return &CredentialHandler{
+		credentialRepo: repositories.NewCredentialRepository(db),
+	}
+}
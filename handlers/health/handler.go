@@ -0,0 +1,35 @@
+package health
+
+import (
+	"sync/atomic"
+	"time"
+
+	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/services/cluster"
+
+	"gorm.io/gorm"
+)
+
+// pingTimeout bounds the database check of a readiness probe
+const pingTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	db           *gorm.DB
+	runsAPI      bool // Socket server expected
+	runsWorkers  bool // Task queue expected
+	shuttingDown atomic.Bool
+}
+
+func NewHealthHandler(config *cluster.Config) *HealthHandler {
+	return &HealthHandler{
+		db:          db.GetDB(),
+		runsAPI:     config.RunsAPI(),
+		runsWorkers: config.RunsWorkers(),
+	}
+}
+
+// MarkShuttingDown makes readiness fail so no new traffic is routed to this
+// instance while it shuts down
+func (h *HealthHandler) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
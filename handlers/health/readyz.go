@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"net/http"
+
+	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/services/socket"
+	"sykell-challenge/backend/services/taskq"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+// check is the result of one readiness check
+type check struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// GET /readyz - Readiness probe: dependencies are reachable and the node can
+// take work; fails while the server shuts down
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	checks := map[string]check{
+		"database":   h.checkDatabase(c.Request.Context()),
+		"migrations": checkCondition(db.Migrated(), "migrations have not been applied"),
+		"shutdown":   checkCondition(!h.shuttingDown.Load(), "server is shutting down"),
+	}
+	if h.runsWorkers {
+		checks["taskQueue"] = checkCondition(taskq.Started(), "task queue is not started")
+	}
+	if h.runsAPI {
+		checks["socketServer"] = checkCondition(socket.GetServer() != nil, "socket server is not initialized")
+	}
+
+	status, code := statusOK, http.StatusOK
+	for _, result := range checks {
+		if result.Status != statusOK {
+			status, code = statusUnavailable, http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(code, gin.H{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+func (h *HealthHandler) checkDatabase(ctx context.Context) check {
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return check{Status: statusUnavailable, Error: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return check{Status: statusUnavailable, Error: err.Error()}
+	}
+	return check{Status: statusOK}
+}
+
+func checkCondition(ok bool, reason string) check {
+	if !ok {
+		return check{Status: statusUnavailable, Error: reason}
+	}
+	return check{Status: statusOK}
+}
@@ -28,8 +28,8 @@ func (h *URLHandler) CreateURL(c *gin.Context) {
 	}
 
 	// Validate status enum
-	if url.Status != "queued" && url.Status != "running" && url.Status != "done" && url.Status != "error" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of: queued, running, done, error"})
+	if url.Status != "queued" && url.Status != "running" && url.Status != "paused" && url.Status != "done" && url.Status != "error" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of: queued, running, paused, done, error"})
 		return
 	}
 
@@ -15,7 +15,7 @@ func (h *URLHandler) GetURLStats(c *gin.Context) {
 	}
 
 	// Get counts by status
-	statuses := []string{"queued", "running", "done", "error"}
+	statuses := []string{"queued", "running", "paused", "done", "error"}
 	statusCounts := make(map[string]int)
 
 	for _, status := range statuses {
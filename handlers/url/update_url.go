@@ -37,8 +37,8 @@ func (h *URLHandler) UpdateURL(c *gin.Context) {
 
 	// Validate status if provided
 	if updateData.Status != "" {
-		if updateData.Status != "queued" && updateData.Status != "running" && updateData.Status != "done" && updateData.Status != "error" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of: queued, running, done, error"})
+		if updateData.Status != "queued" && updateData.Status != "running" && updateData.Status != "paused" && updateData.Status != "done" && updateData.Status != "error" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of: queued, running, paused, done, error"})
 			return
 		}
 	}
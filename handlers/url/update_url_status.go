@@ -26,8 +26,8 @@ func (h *URLHandler) UpdateURLStatus(c *gin.Context) {
 	}
 
 	// Validate status
-	if statusUpdate.Status != "queued" && statusUpdate.Status != "running" && statusUpdate.Status != "done" && statusUpdate.Status != "error" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of: queued, running, done, error"})
+	if statusUpdate.Status != "queued" && statusUpdate.Status != "running" && statusUpdate.Status != "paused" && statusUpdate.Status != "done" && statusUpdate.Status != "error" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of: queued, running, paused, done, error"})
 		return
 	}
 
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/services/webhook"
+	"sykell-challenge/backend/utils/egress"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /webhooks - Register a webhook; the signing secret is only returned here
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.WebhookCreateRequest
+	if !helpers.ValidateJSONBinding(c, &req) {
+		return
+	}
+
+	if err := egress.DefaultGuard().CheckURL(c.Request.Context(), req.URL); err != nil {
+		helpers.SendBadRequestError(c, "Webhook URL is not allowed: "+err.Error())
+		return
+	}
+
+	secret := string(req.Secret)
+	if secret == "" {
+		generated, err := webhook.GenerateSecret()
+		if err != nil {
+			helpers.SendInternalError(c, "Failed to generate webhook secret")
+			return
+		}
+		secret = generated
+	}
+
+	hook := models.Webhook{
+		UserID:      userID,
+		URL:         req.URL,
+		Description: req.Description,
+		Events:      models.WebhookEvents(req.Events),
+		Secret:      models.Secret(secret),
+		Active:      true,
+	}
+
+	if err := h.webhookRepo.Create(&hook); err != nil {
+		helpers.SendInternalError(c, "Failed to create webhook")
+		return
+	}
+
+	helpers.SendCreatedResponse(c, gin.H{"data": hook, "secret": secret})
+}
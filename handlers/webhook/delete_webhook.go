@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DELETE /webhooks/:id - Delete one of the current user's webhooks
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, ok := helpers.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if helpers.HandleDBError(c, h.webhookRepo.Delete(id, userID), "Webhook not found") {
+		return
+	}
+
+	helpers.SendSuccessResponse(c, gin.H{"message": "Webhook deleted successfully"})
+}
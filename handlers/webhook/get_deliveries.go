@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /webhooks/:id/deliveries - List recent deliveries of a webhook
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, ok := helpers.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.webhookRepo.GetByIDForUser(id, userID); helpers.HandleDBError(c, err, "Webhook not found") {
+		return
+	}
+
+	limit := helpers.ParseLimitQuery(c, 50, 200)
+	deliveries, err := h.webhookRepo.GetDeliveries(id, limit)
+	if err != nil {
+		helpers.SendInternalError(c, "Failed to fetch deliveries")
+		return
+	}
+
+	helpers.SendSuccessResponse(c, gin.H{"data": deliveries})
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /webhooks - List the current user's webhooks (secrets are never returned)
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	webhooks, err := h.webhookRepo.GetByUserID(userID)
+	if err != nil {
+		helpers.SendInternalError(c, "Failed to fetch webhooks")
+		return
+	}
+
+	helpers.SendSuccessResponse(c, gin.H{"data": webhooks})
+}
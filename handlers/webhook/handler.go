@@ -0,0 +1,17 @@
+package webhook
+
+import (
+	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/repositories"
+)
+
+type WebhookHandler struct {
+	webhookRepo *repositories.WebhookRepository
+}
+
+func NewWebhookHandler() *WebhookHandler {
+	db := db.GetDB()
+	return &WebhookHandler{
+		webhookRepo: repositories.NewWebhookRepository(db),
+	}
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/services/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /webhooks/:id/deliveries/:deliveryId/replay - Send an earlier delivery's payload again
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, ok := helpers.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := helpers.ParseIDParam(c, "deliveryId")
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhookRepo.GetByIDForUser(id, userID)
+	if helpers.HandleDBError(c, err, "Webhook not found") {
+		return
+	}
+
+	original, err := h.webhookRepo.GetDelivery(deliveryID, hook.ID)
+	if helpers.HandleDBError(c, err, "Delivery not found") {
+		return
+	}
+
+	dispatcher := webhook.GetDispatcher()
+	if dispatcher == nil {
+		helpers.SendInternalError(c, "Webhook delivery is not running")
+		return
+	}
+
+	delivery, err := dispatcher.Replay(hook, original)
+	if err != nil {
+		helpers.SendInternalError(c, "Failed to replay delivery")
+		return
+	}
+
+	helpers.SendSuccessResponse(c, gin.H{"data": delivery})
+}
@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"net/http"
+	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/helpers"
+	"sykell-challenge/backend/services/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /webhooks/:id/test - Send a test event to a webhook and report the result
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	userID, ok := auth.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, ok := helpers.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhookRepo.GetByIDForUser(id, userID)
+	if helpers.HandleDBError(c, err, "Webhook not found") {
+		return
+	}
+
+	dispatcher := webhook.GetDispatcher()
+	if dispatcher == nil {
+		helpers.SendInternalError(c, "Webhook delivery is not running")
+		return
+	}
+
+	delivery, err := dispatcher.SendTest(hook)
+	if err != nil {
+		helpers.SendInternalError(c, "Failed to send test delivery")
+		return
+	}
+
+	helpers.SendSuccessResponse(c, gin.H{"data": delivery})
+}
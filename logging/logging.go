@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// redactedKeys are attribute keys whose values never reach the log
+var redactedKeys = []string{"password", "secret", "token", "authorization", "cookie", "api_key", "apikey"}
+
+// Config holds logging configuration
+type Config struct {
+	Level  slog.Level `yaml:"level"`
+	Format string     `yaml:"format"` // FormatJSON or FormatText
+}
+
+// DefaultConfig returns the default logging configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Level:  slog.LevelInfo,
+		Format: FormatJSON,
+	}
+}
+
+// Init installs the process-wide logger. The standard library log package
+// writes through it as well.
+func Init(config *Config) *slog.Logger {
+	logger := slog.New(NewHandler(os.Stdout, config))
+	slog.SetDefault(logger)
+	return logger
+}
+
+// NewHandler creates a handler that writes in the configured format and redacts secrets
+func NewHandler(w io.Writer, config *Config) slog.Handler {
+	options := &slog.HandlerOptions{
+		Level:       config.Level,
+		ReplaceAttr: redact,
+	}
+
+	if config.Format == FormatText {
+		return slog.NewTextHandler(w, options)
+	}
+	return slog.NewJSONHandler(w, options)
+}
+
+// redact hides the values of attributes whose key names a secret
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+	for _, secret := range redactedKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(attr.Key, "[REDACTED]")
+		}
+	}
+	return attr
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a context carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
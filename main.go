@@ -2,57 +2,219 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	"sykell-challenge/backend/auth"
+	"sykell-challenge/backend/config"
 	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/handlers/admin"
 	"sykell-challenge/backend/handlers/crawl"
+	"sykell-challenge/backend/handlers/credential"
+	"sykell-challenge/backend/handlers/health"
 	"sykell-challenge/backend/handlers/url"
 	"sykell-challenge/backend/handlers/user"
+	"sykell-challenge/backend/handlers/webhook"
+	"sykell-challenge/backend/logging"
+	"sykell-challenge/backend/services/broker"
+	"sykell-challenge/backend/services/cluster"
+	crawlService "sykell-challenge/backend/services/crawl"
+	"sykell-challenge/backend/services/events"
+	"sykell-challenge/backend/services/janitor"
+	"sykell-challenge/backend/services/metrics"
 	"sykell-challenge/backend/services/socket"
 	"sykell-challenge/backend/services/taskq"
+	"sykell-challenge/backend/services/telemetry"
+	webhookService "sykell-challenge/backend/services/webhook"
+	"sykell-challenge/backend/utils/egress"
+	"sykell-challenge/backend/utils/secrets"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	db.MigrateAll()
+	// All settings are read and validated once, up front
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	logging.Init(&cfg.Logging)
+	if cfg.Credentials.EncryptionKey == config.DevEncryptionKey {
+		slog.Warn("Using the public development credentials encryption key; set CREDENTIALS_ENCRYPTION_KEY in production")
+	}
+
+	clusterConfig := &cfg.Cluster
+	cluster.Init(clusterConfig)
+
+	auth.Init(&cfg.Auth)
+	secrets.Init(cfg.Credentials.EncryptionKey)
+	egress.Configure(&cfg.Egress)
+	db.Configure(&cfg.Database)
+	crawlService.SetRetryPolicy(cfg.Retry)
+
+	// Trace requests, queries, crawls and outbound HTTP when an exporter is configured
+	shutdownTracing := telemetry.Init(&cfg.Tracing, clusterConfig.NodeID)
+
+	// A failed migration keeps the node unready instead of stopping it
+	if err := db.MigrateAll(); err != nil {
+		slog.Error("Migration failed", "error", err)
+	}
+
+	// Job control and crawl events reach the other server instances through the broker
+	brokerConfig := &cfg.Broker
+	if brokerConfig.Kind == broker.KindLocal && clusterConfig.Mode != cluster.ModeAll {
+		slog.Warn("Other nodes are unreachable without a shared broker", "mode", clusterConfig.Mode, "broker", brokerConfig.Kind, "required", broker.KindDatabase)
+	}
+	messageBroker := broker.Init(brokerConfig, clusterConfig.NodeID, db.GetDB())
+	messageBroker.Start()
+
+	// Deliver webhook notifications in the background
+	webhookDispatcher := webhookService.InitDispatcher(&cfg.Webhooks, db.GetDB())
+	webhookDispatcher.Start()
 
-	// Initialize task queue for background crawling
-	taskq.InitTaskQueue()
+	// Crawl events fan out to webhooks, the audit log and metrics
+	events.Subscribe(webhookService.EventSink{})
+	events.Subscribe(events.AuditSink{})
+	events.Subscribe(metrics.EventSink{})
+
+	// Recent events are numbered and kept so that streaming and Socket.IO
+	// clients can catch up after reconnecting
+	eventHistory := events.InitHistory(&cfg.Events)
+	if clusterConfig.RunsAPI() {
+		eventHistory.OnRecord(socket.ForwardRecord)
+	}
+	if brokerConfig.Kind != broker.KindLocal {
+		cluster.BridgeEvents(eventHistory)
+	} else {
+		events.Subscribe(eventHistory)
+	}
+
+	// Initialize task queue for background crawling, fed with jobs queued by any node
+	var jobClaimer *crawlService.Claimer
+	if clusterConfig.RunsWorkers() {
+		taskq.InitTaskQueue(&cfg.TaskQueue)
+		cluster.HandleJobControl()
+		jobClaimer = crawlService.NewClaimer(clusterConfig)
+		jobClaimer.Start()
+	}
+
+	// Reap stuck jobs and clean up old ones in the background; only workers
+	// know which jobs are still alive
+	var jobJanitor *janitor.Janitor
+	if clusterConfig.RunsWorkers() {
+		jobJanitor = janitor.NewJanitor(&cfg.Janitor, db.GetDB())
+		jobJanitor.Start()
+	}
+
+	// Export connection pool statistics alongside the other metrics
+	if sqlDB, err := db.GetDB().DB(); err == nil {
+		metrics.RegisterDB(sqlDB)
+	}
 
+	// Probes for the orchestrator; readiness fails once shutdown begins
+	healthHandler := health.NewHealthHandler(clusterConfig)
+
+	// Worker nodes only run crawls and serve their metrics and probes; their
+	// events reach clients through the API nodes
+	handler := newWorkerRouter(healthHandler)
+	if clusterConfig.RunsAPI() {
+		handler = newRouter(cfg.Server.AllowedOrigins, healthHandler)
+	}
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:    cfg.Server.Addr(),
+		Handler: handler,
+	}
+
+	// Start server in a goroutine
+	go func() {
+		slog.Info("Server starting", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Server failed to start", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	slog.Info("Shutting down server")
+	healthHandler.MarkShuttingDown()
+
+	// Stop background maintenance, shut down the task queue and hand jobs that
+	// did not start back to the other workers, then stop webhook delivery;
+	// undelivered webhooks stay in the database for the next start
+	if jobJanitor != nil {
+		jobJanitor.Stop()
+	}
+	taskq.ShutdownTaskQueue()
+	if jobClaimer != nil {
+		jobClaimer.Stop()
+	}
+	webhookDispatcher.Stop()
+
+	// End open event streams so they do not hold up the shutdown
+	eventHistory.Close()
+
+	// Shutdown HTTP server
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("Server forced to shutdown", "error", err)
+		os.Exit(1)
+	}
+
+	messageBroker.Stop()
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Warn("Failed to flush traces", "error", err)
+	}
+
+	slog.Info("Server exited")
+}
+
+// newRouter sets up the HTTP API and the Socket.IO endpoint
+func newRouter(allowedOrigins []string, healthHandler *health.HealthHandler) *gin.Engine {
 	// Initialize handlers
 	urlHandler := url.NewURLHandler()
 	userHandler := user.NewUserHandler()
 	crawlHandler := crawl.NewCrawlHandler()
+	credentialHandler := credential.NewCredentialHandler()
+	webhookHandler := webhook.NewWebhookHandler()
+	adminHandler := admin.NewAdminHandler()
 
-	router := gin.Default()
+	router := newEngine()
 
-	// Configure CORS with environment-based origins
+	// Configure CORS for the configured origins
 	corsConfig := cors.Config{
+		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Last-Event-ID", logging.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", logging.RequestIDHeader},
 		AllowCredentials: true,
 	}
 
-	// Set allowed origins based on environment
-	allowedOrigins := os.Getenv("ALLOWED_ORIGINS")
-	if allowedOrigins == "" {
-		panic("ALLOWED_ORIGINS environment variable is required!")
-	} else {
-		corsConfig.AllowOrigins = strings.Split(allowedOrigins, ",")
-	}
-
 	router.Use(cors.New(corsConfig))
 
+	// Prometheus metrics (unauthenticated; keep the port off the public network)
+	router.GET("/metrics", metrics.Handler())
+
+	// Liveness and readiness probes (unauthenticated)
+	router.GET("/healthz", healthHandler.Healthz)
+	router.GET("/readyz", healthHandler.Readyz)
+
 	// Public user routes (no authentication required)
 	router.POST("/users", userHandler.CreateUser)
 	router.POST("/users/login", userHandler.LoginUser)
@@ -83,42 +245,63 @@ func main() {
 
 	// Crawl routes (protected)
 	protected.POST("/crawl", crawlHandler.HandleCrawlURL)
+	protected.GET("/crawl/:jobId", crawlHandler.HandleGetCrawlJob)
 	protected.DELETE("/crawl/:jobId", crawlHandler.HandleCancelCrawl)
+	protected.POST("/crawl/:jobId/recrawl", crawlHandler.HandleRecrawl)
+	protected.POST("/crawl/:jobId/pause", crawlHandler.HandlePauseCrawl)
+	protected.POST("/crawl/:jobId/resume", crawlHandler.HandleResumeCrawl)
+	protected.PUT("/crawl/:jobId/priority", crawlHandler.HandleSetPriority)
 	protected.GET("/crawl-history", crawlHandler.HandleGetAllCrawlJobs)
+	protected.GET("/crawl-queue", crawlHandler.HandleGetQueue)
 
-	server := socket.InitSocketServer()
+	// Event streams (Server-Sent Events); the token may also be passed as a query parameter
+	streams := router.Group("/")
+	streams.Use(auth.JWTStreamMiddleware())
+	streams.GET("/events", crawlHandler.HandleStreamEvents)
+	streams.GET("/crawl/:jobId/events", crawlHandler.HandleStreamJobEvents)
 
-	router.Any("/socket.io/*any", gin.WrapH(server.ServeHandler(nil)))
+	// Crawl credential routes (protected)
+	protected.POST("/credentials", credentialHandler.CreateCredential)
+	protected.GET("/credentials", credentialHandler.GetCredentials)
+	protected.DELETE("/credentials/:id", credentialHandler.DeleteCredential)
 
-	// Create HTTP server
-	srv := &http.Server{
-		Addr:    "0.0.0.0:8080",
-		Handler: router,
-	}
+	// Webhook routes (protected)
+	protected.POST("/webhooks", webhookHandler.CreateWebhook)
+	protected.GET("/webhooks", webhookHandler.GetWebhooks)
+	protected.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+	protected.POST("/webhooks/:id/test", webhookHandler.TestWebhook)
+	protected.GET("/webhooks/:id/deliveries", webhookHandler.GetDeliveries)
+	protected.POST("/webhooks/:id/deliveries/:deliveryId/replay", webhookHandler.ReplayDelivery)
 
-	// Start server in a goroutine
-	go func() {
-		log.Println("Server starting on :8080")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal("Server failed to start:", err)
-		}
-	}()
+	// Admin routes (protected, admin users only)
+	admins := protected.Group("/admin")
+	admins.Use(adminHandler.RequireAdmin())
+	admins.GET("/queue", adminHandler.GetQueue)
+	admins.PUT("/queue/workers", adminHandler.ResizeWorkers)
+	admins.GET("/proxies", adminHandler.GetProxies)
 
-	// Wait for interrupt signal to gracefully shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
+	server := socket.InitSocketServer(allowedOrigins)
 
-	// Shutdown task queue first
-	taskq.ShutdownTaskQueue()
+	router.Any("/socket.io/*any", gin.WrapH(server.ServeHandler(nil)))
 
-	// Shutdown HTTP server
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
-	}
+	return router
+}
+
+// newWorkerRouter serves the metrics and probes of a worker node
+func newWorkerRouter(healthHandler *health.HealthHandler) *gin.Engine {
+	router := newEngine()
+	router.GET("/metrics", metrics.Handler())
+	router.GET("/healthz", healthHandler.Healthz)
+	router.GET("/readyz", healthHandler.Readyz)
+	return router
+}
 
-	log.Println("Server exited")
+// newEngine creates a router that logs every request with its request ID
+func newEngine() *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(telemetry.GinMiddleware())
+	router.Use(logging.RequestLogger())
+	router.Use(metrics.GinMiddleware())
+	return router
 }
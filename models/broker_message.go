@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// BrokerMessage is a message passed between server instances through the database
+type BrokerMessage struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Topic     string    `json:"topic" gorm:"size:64;not null;index"`
+	Origin    string    `json:"origin" gorm:"size:128;not null"` // Node that published the message
+	Data      string    `json:"data" gorm:"type:mediumtext;not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CrawlCheckpoint is the saved state of a paused crawl: the analyzed page,
+// the links checked so far and the links still to check
+type CrawlCheckpoint struct {
+	Page            URL       `json:"page"`         // Main page analysis including the links already checked
+	PendingLinks    []string  `json:"pendingLinks"` // Link-check frontier
+	LinksDiscovered int       `json:"linksDiscovered"`
+	BrokenLinks     int       `json:"brokenLinks"`
+	Outcome         string    `json:"outcome,omitempty"` // Truncation recorded before the pause
+	OutcomeReason   string    `json:"outcomeReason,omitempty"`
+	PausedAt        time.Time `json:"pausedAt"`
+}
+
+// Value implements the driver.Valuer interface
+func (c CrawlCheckpoint) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface
+func (c *CrawlCheckpoint) Scan(value interface{}) error {
+	if value == nil {
+		*c = CrawlCheckpoint{}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("cannot scan %T into CrawlCheckpoint", value)
+	}
+}
@@ -6,13 +6,50 @@ import (
 	"gorm.io/gorm"
 )
 
+// Crawl job priorities, highest runs first among a user's queued jobs
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// PriorityRank orders priorities for the scheduler
+func PriorityRank(priority string) int {
+	switch priority {
+	case PriorityHigh:
+		return 2
+	case PriorityLow:
+		return 0
+	default:
+		return 1
+	}
+}
+
 type CrawlJob struct {
 	gorm.Model
-	URL         string     `json:"url" gorm:"not null"`
-	URLID       uint       `json:"urlId" gorm:"index"`
-	Status      string     `json:"status" gorm:"type:enum('queued','running','completed','cancelled','error');default:'queued';not null"`
-	StartedAt   *time.Time `json:"startedAt" gorm:"default:null"`
-	CompletedAt *time.Time `json:"completedAt" gorm:"default:null"`
-	Progress    int        `json:"progress" gorm:"default:0"` // Progress percentage
-	ErrorMsg    string     `json:"errorMessage,omitempty"`
+	URL         string       `json:"url" gorm:"not null"`
+	URLID       uint         `json:"urlId" gorm:"index"`
+	UserID      uint         `json:"userId" gorm:"index"` // User who submitted the crawl
+	Status      string       `json:"status" gorm:"type:enum('queued','running','paused','completed','truncated','timeout','cancelled','error');default:'queued';not null"`
+	StartedAt   *time.Time   `json:"startedAt" gorm:"default:null"`
+	CompletedAt *time.Time   `json:"completedAt" gorm:"default:null"`
+	Priority    string       `json:"priority" gorm:"type:enum('low','normal','high');default:'normal';not null"`
+	Progress    int          `json:"progress" gorm:"default:0"` // Progress percentage
+	ErrorMsg    string       `json:"errorMessage,omitempty"`
+	Options     CrawlOptions `json:"options" gorm:"type:json"`                 // Request settings reused by re-crawls
+	Attempts    int          `json:"attempts" gorm:"default:0"`                // Crawl attempts made so far
+	LastError   string       `json:"lastError,omitempty"`                      // Error of the most recent failed attempt
+	WorkerID    string       `json:"workerId,omitempty" gorm:"size:128;index"` // Node that claimed the job; empty while unclaimed
+	TraceParent string       `json:"-" gorm:"size:64"`                         // W3C trace context of the request that queued the job
+
+	// Set while the job is paused; the resumed crawl continues from here
+	Checkpoint *CrawlCheckpoint `json:"checkpoint,omitempty" gorm:"type:json"`
+
+	// Progress counters, updated while the crawl runs
+	PagesFetched    int `json:"pagesFetched" gorm:"default:0"`
+	LinksDiscovered int `json:"linksDiscovered" gorm:"default:0"`
+	LinksChecked    int `json:"linksChecked" gorm:"default:0"`
+	BrokenLinks     int `json:"brokenLinks" gorm:"default:0"`
+	LinksRemaining  int `json:"linksRemaining" gorm:"default:0"`
+	ETASeconds      int `json:"etaSeconds" gorm:"default:0"` // Estimated seconds until link checking finishes
 }
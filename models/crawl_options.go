@@ -0,0 +1,207 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultCrawlTimeout      = 10 * time.Second
+	DefaultCrawlMaxRedirects = 5
+
+	DefaultCrawlMaxBodyBytes = 10 * 1024 * 1024
+	DefaultCrawlMaxDuration  = 5 * time.Minute
+	DefaultCrawlMaxLinks     = 500
+	DefaultCrawlMaxRequests  = 1000
+)
+
+// CrawlOptions holds per-crawl request settings, persisted on the CrawlJob so re-crawls reuse them
+type CrawlOptions struct {
+	UserAgent          string            `json:"userAgent,omitempty" binding:"omitempty,max=512"`
+	Headers            map[string]string `json:"headers,omitempty" binding:"omitempty,max=50"`
+	Cookies            []CrawlCookie     `json:"cookies,omitempty" binding:"omitempty,max=50,dive"`
+	BasicAuth          *BasicAuth        `json:"basicAuth,omitempty"`
+	TimeoutSeconds     int               `json:"timeoutSeconds,omitempty" binding:"omitempty,min=1,max=120"`
+	MaxRedirects       *int              `json:"maxRedirects,omitempty" binding:"omitempty,min=0,max=20"`
+	CheckExternalLinks *bool             `json:"checkExternalLinks,omitempty"`
+	Login              *LoginStep        `json:"login,omitempty"`
+	Proxies            []string          `json:"proxies,omitempty" binding:"omitempty,max=20"` // Overrides the global proxy pool
+	NoProxy            bool              `json:"noProxy,omitempty"`                            // Bypasses the global proxy pool
+	Limits             CrawlLimits       `json:"limits"`
+}
+
+// CrawlLimits bounds the resources a single crawl job may use; zero values use the defaults
+type CrawlLimits struct {
+	MaxBodyBytes       int64 `json:"maxBodyBytes,omitempty" binding:"omitempty,min=1024,max=52428800"`
+	MaxDurationSeconds int   `json:"maxDurationSeconds,omitempty" binding:"omitempty,min=10,max=3600"`
+	MaxLinks           int   `json:"maxLinks,omitempty" binding:"omitempty,min=1,max=5000"`
+	MaxRequests        int   `json:"maxRequests,omitempty" binding:"omitempty,min=1,max=10000"`
+}
+
+// BodyLimit returns the maximum number of response body bytes to download
+func (l CrawlLimits) BodyLimit() int64 {
+	if l.MaxBodyBytes > 0 {
+		return l.MaxBodyBytes
+	}
+	return DefaultCrawlMaxBodyBytes
+}
+
+// DurationLimit returns the maximum total run time of a crawl job
+func (l CrawlLimits) DurationLimit() time.Duration {
+	if l.MaxDurationSeconds > 0 {
+		return time.Duration(l.MaxDurationSeconds) * time.Second
+	}
+	return DefaultCrawlMaxDuration
+}
+
+// LinkLimit returns the maximum number of links to check
+func (l CrawlLimits) LinkLimit() int {
+	if l.MaxLinks > 0 {
+		return l.MaxLinks
+	}
+	return DefaultCrawlMaxLinks
+}
+
+// RequestLimit returns the maximum number of outbound HTTP requests, redirects included
+func (l CrawlLimits) RequestLimit() int {
+	if l.MaxRequests > 0 {
+		return l.MaxRequests
+	}
+	return DefaultCrawlMaxRequests
+}
+
+type CrawlCookie struct {
+	Name  string `json:"name" binding:"required,max=256"`
+	Value string `json:"value" binding:"max=4096"`
+}
+
+type BasicAuth struct {
+	Username string `json:"username" binding:"required,max=256"`
+	Password Secret `json:"password,omitempty" binding:"max=256"`
+}
+
+// LoginStep describes a form login performed before the crawl; the session
+// cookies it produces are reused for the target page and internal links
+type LoginStep struct {
+	FormURL       string            `json:"formUrl" binding:"required,url"`
+	ActionURL     string            `json:"actionUrl,omitempty" binding:"omitempty,url"` // Defaults to the form's action
+	UsernameField string            `json:"usernameField" binding:"required,max=128"`
+	PasswordField string            `json:"passwordField" binding:"required,max=128"`
+	ExtraFields   map[string]string `json:"extraFields,omitempty" binding:"omitempty,max=20"`
+	CredentialID  uint              `json:"credentialId" binding:"required"`
+	SuccessCheck  LoginSuccessCheck `json:"successCheck"`
+}
+
+// LoginSuccessCheck lists the conditions a login response must meet; all set conditions must hold
+type LoginSuccessCheck struct {
+	URLContains     string `json:"urlContains,omitempty" binding:"max=512"`     // Final URL after redirects contains this
+	TextContains    string `json:"textContains,omitempty" binding:"max=512"`    // Response body contains this
+	TextNotContains string `json:"textNotContains,omitempty" binding:"max=512"` // Response body does not contain this, e.g. "Invalid password"
+	CookieName      string `json:"cookieName,omitempty" binding:"max=256"`      // Session cookie that must be set
+}
+
+// crawlOptionsRecord has the same fields as CrawlOptions without its methods,
+// so the database representation keeps the credentials the API hides
+type crawlOptionsRecord CrawlOptions
+
+// Timeout returns the per-request timeout, falling back to the default
+func (o CrawlOptions) Timeout() time.Duration {
+	if o.TimeoutSeconds > 0 {
+		return time.Duration(o.TimeoutSeconds) * time.Second
+	}
+	return DefaultCrawlTimeout
+}
+
+// RedirectLimit returns the maximum number of redirects to follow
+func (o CrawlOptions) RedirectLimit() int {
+	if o.MaxRedirects != nil {
+		return *o.MaxRedirects
+	}
+	return DefaultCrawlMaxRedirects
+}
+
+// ShouldCheckExternalLinks reports whether external links are pinged (default true)
+func (o CrawlOptions) ShouldCheckExternalLinks() bool {
+	return o.CheckExternalLinks == nil || *o.CheckExternalLinks
+}
+
+// String keeps header values and credentials out of fmt and log output
+func (o CrawlOptions) String() string {
+	headers := make([]string, 0, len(o.Headers))
+	for name := range o.Headers {
+		headers = append(headers, name)
+	}
+	sort.Strings(headers)
+
+	return fmt.Sprintf("{UserAgent:%q Headers:%v Cookies:%d BasicAuth:%t Timeout:%s MaxRedirects:%d CheckExternalLinks:%t Login:%t Proxies:%d NoProxy:%t Limits:%+v}",
+		o.UserAgent, headers, len(o.Cookies), o.BasicAuth != nil, o.Timeout(), o.RedirectLimit(), o.ShouldCheckExternalLinks(), o.Login != nil, len(o.Proxies), o.NoProxy, o.Limits)
+}
+
+// MarshalJSON hides header and cookie values, the basic-auth password and
+// proxy credentials from API responses; names are kept
+func (o CrawlOptions) MarshalJSON() ([]byte, error) {
+	redacted := crawlOptionsRecord(o)
+	if len(redacted.Headers) > 0 {
+		redacted.Headers = make(map[string]string, len(o.Headers))
+		for name := range o.Headers {
+			redacted.Headers[name] = redactedSecret
+		}
+	}
+	if len(redacted.Cookies) > 0 {
+		redacted.Cookies = make([]CrawlCookie, len(o.Cookies))
+		for i, cookie := range o.Cookies {
+			redacted.Cookies[i] = CrawlCookie{Name: cookie.Name, Value: redactedSecret}
+		}
+	}
+	if redacted.BasicAuth != nil {
+		basicAuth := *redacted.BasicAuth
+		basicAuth.Password = ""
+		redacted.BasicAuth = &basicAuth
+	}
+	if len(redacted.Proxies) > 0 {
+		redacted.Proxies = make([]string, len(o.Proxies))
+		for i, proxy := range o.Proxies {
+			if proxyURL, err := url.Parse(proxy); err == nil {
+				proxy = proxyURL.Redacted()
+			}
+			redacted.Proxies[i] = proxy
+		}
+	}
+	return json.Marshal(redacted)
+}
+
+// Value implements the driver.Valuer interface
+func (o CrawlOptions) Value() (driver.Value, error) {
+	return json.Marshal(crawlOptionsRecord(o))
+}
+
+// Scan implements the sql.Scanner interface
+func (o *CrawlOptions) Scan(value interface{}) error {
+	if value == nil {
+		*o = CrawlOptions{}
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into CrawlOptions", value)
+	}
+
+	if err := json.Unmarshal(data, (*crawlOptionsRecord)(o)); err != nil {
+		return err
+	}
+	if o.BasicAuth != nil {
+		// Stored encrypted by Secret.MarshalJSON
+		return o.BasicAuth.Password.set(string(o.BasicAuth.Password))
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Credential is a stored login used by crawl login steps and basic auth.
+// The password is encrypted at rest and never returned by the API.
+type Credential struct {
+	gorm.Model
+	UserID   uint   `json:"userId" gorm:"index;not null"`
+	Name     string `json:"name" gorm:"type:varchar(100);not null"`
+	Username string `json:"username" gorm:"type:varchar(255);not null"`
+	Password Secret `json:"-" gorm:"type:text;not null"`
+}
+
+// CredentialCreateRequest represents the data needed to store a credential
+type CredentialCreateRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Username string `json:"username" binding:"required,max=255"`
+	Password Secret `json:"password" binding:"required,max=1024"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type Form struct {
+	Type   string `json:"type"`   // login, signup, password_reset, search, newsletter, other
+	Action string `json:"action"` // Absolute URL the form submits to
+	Method string `json:"method"` // GET, POST, or OAUTH for third-party sign-in buttons
+	Source string `json:"source"` // form, standalone, oauth
+}
+
+type Forms []Form
+
+// Value implements the driver.Valuer interface
+func (f Forms) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+// Scan implements the sql.Scanner interface
+func (f *Forms) Scan(value interface{}) error {
+	if value == nil {
+		*f = Forms{}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	default:
+		return fmt.Errorf("cannot scan %T into Forms", value)
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"sykell-challenge/backend/utils/secrets"
+)
+
+// Secret is a sensitive string that is encrypted at rest and never printed.
+// Plaintext is only available through an explicit string(secret) conversion.
+type Secret string
+
+const redactedSecret = "[REDACTED]"
+
+// String keeps secrets out of fmt and log output
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redactedSecret
+}
+
+// GoString keeps secrets out of %#v output
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+// MarshalJSON emits the encrypted form, so secrets are never serialized in
+// plaintext; types storing secrets as JSON decrypt them with set after decoding
+func (s Secret) MarshalJSON() ([]byte, error) {
+	if s == "" {
+		return json.Marshal("")
+	}
+
+	encrypted, err := secrets.Encrypt(string(s))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encrypted)
+}
+
+// UnmarshalJSON takes the value as plaintext, as sent in API requests.
+// Ciphertext copied from another record is not decrypted.
+func (s *Secret) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = Secret(value)
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (s Secret) Value() (driver.Value, error) {
+	if s == "" {
+		return "", nil
+	}
+	return secrets.Encrypt(string(s))
+}
+
+// Scan implements the sql.Scanner interface
+func (s *Secret) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = ""
+		return nil
+	case []byte:
+		return s.set(string(v))
+	case string:
+		return s.set(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Secret", value)
+	}
+}
+
+// set stores a value read from the database, decrypting it unless it
+// predates encryption
+func (s *Secret) set(value string) error {
+	if !secrets.IsEncrypted(value) {
+		*s = Secret(value)
+		return nil
+	}
+
+	plaintext, err := secrets.Decrypt(value)
+	if err != nil {
+		return err
+	}
+	*s = Secret(plaintext)
+	return nil
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"sykell-challenge/backend/utils/secrets"
+)
+
+func init() {
+	secrets.Init("test-encryption-key")
+}
+
+func TestSecretRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Secret
+	}{
+		{name: "empty", value: ""},
+		{name: "ascii", value: "hunter2"},
+		{name: "unicode", value: "pässwörd 🔑"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := tt.value.Value()
+			if err != nil {
+				t.Fatalf("Value() error = %v", err)
+			}
+			if tt.value != "" && !secrets.IsEncrypted(stored.(string)) {
+				t.Fatalf("Value() = %q, want an encrypted value", stored)
+			}
+
+			var scanned Secret
+			if err := scanned.Scan([]byte(stored.(string))); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if scanned != tt.value {
+				t.Fatalf("Scan() = %q, want %q", string(scanned), string(tt.value))
+			}
+
+			encoded, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if tt.value != "" && strings.Contains(string(encoded), string(tt.value)) {
+				t.Fatalf("MarshalJSON() = %s, contains the plaintext", encoded)
+			}
+		})
+	}
+}
+
+func TestSecretAcceptsPlaintext(t *testing.T) {
+	var fromJSON Secret
+	if err := json.Unmarshal([]byte(`"hunter2"`), &fromJSON); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if fromJSON != "hunter2" {
+		t.Fatalf("UnmarshalJSON() = %q, want %q", string(fromJSON), "hunter2")
+	}
+
+	var scanned Secret
+	if err := scanned.Scan("legacy"); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if scanned != "legacy" {
+		t.Fatalf("Scan() = %q, want %q", string(scanned), "legacy")
+	}
+}
+
+func TestSecretDoesNotDecryptAPIInput(t *testing.T) {
+	// Ciphertext copied from another record must not reveal or reuse its plaintext
+	encoded, err := json.Marshal(Secret("hunter2"))
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	var fromJSON Secret
+	if err := json.Unmarshal(encoded, &fromJSON); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if fromJSON == "hunter2" || !secrets.IsEncrypted(string(fromJSON)) {
+		t.Fatalf("UnmarshalJSON() = %q, want the ciphertext taken literally", string(fromJSON))
+	}
+}
+
+func TestSecretRejectsTamperedCiphertext(t *testing.T) {
+	encrypted, err := secrets.Encrypt("hunter2")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	tampered := encrypted[:len(encrypted)-4] + "AAAA"
+
+	var scanned Secret
+	if err := scanned.Scan(tampered); err == nil {
+		t.Fatalf("Scan() of a tampered value succeeded with %q", string(scanned))
+	}
+}
+
+func TestSecretRedactedInOutput(t *testing.T) {
+	secret := Secret("hunter2")
+
+	for _, verb := range []string{"%s", "%v", "%+v", "%#v", "%q"} {
+		if output := fmt.Sprintf(verb, secret); strings.Contains(output, "hunter2") {
+			t.Errorf("Sprintf(%q) = %q, contains the plaintext", verb, output)
+		}
+	}
+
+	holder := struct{ Password Secret }{Password: secret}
+	if output := fmt.Sprintf("%+v", holder); strings.Contains(output, "hunter2") {
+		t.Errorf("Sprintf(%%+v) of a struct = %q, contains the plaintext", output)
+	}
+
+	if output := fmt.Sprint(Secret("")); output != "" {
+		t.Errorf("Sprint of an empty secret = %q, want empty", output)
+	}
+}
@@ -6,13 +6,18 @@ import (
 
 type URL struct {
 	gorm.Model
-	URL         string `json:"url" gorm:"not null"`
-	Title       string `json:"title" gorm:"type:varchar(500)"` // Page title
-	Status      string `json:"status" gorm:"type:enum('queued','running','done','error', 'cancelled');default:'queued';not null"`
-	StatusCode  int    `json:"statusCode" gorm:"default:0"` // HTTP status code (200, 404, 500, etc.)
-	HTMLVersion string `json:"htmlVersion"`
-	LoginForm   bool   `json:"loginFormPresent" gorm:"default:false"`
-	Tags        Tags   `json:"tags" gorm:"type:json"`
-	Links       Links  `json:"links" gorm:"type:json"`
-	JobId       string `json:"jobId" gorm:"index"` // ID of the channel/goroutine running the crawl
+	URL           string `json:"url" gorm:"not null"`
+	Title         string `json:"title" gorm:"type:varchar(500)"` // Page title
+	Status        string `json:"status" gorm:"type:enum('queued','running','paused','done','error', 'cancelled');default:'queued';not null"`
+	StatusCode    int    `json:"statusCode" gorm:"default:0"` // HTTP status code (200, 404, 500, etc.)
+	HTMLVersion   string `json:"htmlVersion"`
+	ContentType   string `json:"contentType" gorm:"type:varchar(255)"` // MIME type, e.g. text/html or application/pdf
+	ContentLength int64  `json:"contentLength" gorm:"default:0"`       // Response size in bytes
+	LoginForm     bool   `json:"loginFormPresent" gorm:"default:false"`
+	Forms         Forms  `json:"forms" gorm:"type:json"`                       // Classified forms found on the page
+	AuthRequired  bool   `json:"authRequired" gorm:"default:false"`            // Page answered with HTTP 401/403
+	AuthScheme    string `json:"authScheme,omitempty" gorm:"type:varchar(50)"` // Scheme from WWW-Authenticate, e.g. "basic"
+	Tags          Tags   `json:"tags" gorm:"type:json"`
+	Links         Links  `json:"links" gorm:"type:json"`
+	JobId         string `json:"jobId" gorm:"index"` // ID of the channel/goroutine running the crawl
 }
@@ -14,6 +14,7 @@ type User struct {
 	FirstName    string     `json:"first_name" gorm:"type:varchar(100)"`
 	LastName     string     `json:"last_name" gorm:"type:varchar(100)"`
 	IsActive     bool       `json:"is_active" gorm:"default:true"`
+	IsAdmin      bool       `json:"is_admin" gorm:"default:false"` // Granted in the database only
 	LastLoginAt  *time.Time `json:"last_login_at"`
 }
 
@@ -25,6 +26,7 @@ type UserResponse struct {
 	FirstName   string     `json:"first_name"`
 	LastName    string     `json:"last_name"`
 	IsActive    bool       `json:"is_active"`
+	IsAdmin     bool       `json:"is_admin"`
 	LastLoginAt *time.Time `json:"last_login_at"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
@@ -39,6 +41,7 @@ func (u *User) ToResponse() UserResponse {
 		FirstName:   u.FirstName,
 		LastName:    u.LastName,
 		IsActive:    u.IsActive,
+		IsAdmin:     u.IsAdmin,
 		LastLoginAt: u.LastLoginAt,
 		CreatedAt:   u.CreatedAt,
 		UpdatedAt:   u.UpdatedAt,
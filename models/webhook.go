@@ -0,0 +1,88 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook events a user can subscribe to
+const (
+	WebhookEventCrawlCompleted   = "crawl_completed"
+	WebhookEventCrawlError       = "crawl_error"
+	WebhookEventBrokenLinksFound = "broken_links_found"
+	WebhookEventTest             = "webhook_test" // Sent by the test endpoint regardless of subscriptions
+)
+
+// WebhookEvents is the list of events a webhook is subscribed to
+type WebhookEvents []string
+
+// Value implements the driver.Valuer interface
+func (e WebhookEvents) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+// Scan implements the sql.Scanner interface
+func (e *WebhookEvents) Scan(value interface{}) error {
+	if value == nil {
+		*e = WebhookEvents{}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookEvents", value)
+	}
+}
+
+// Has reports whether the webhook is subscribed to event
+func (e WebhookEvents) Has(event string) bool {
+	for _, subscribed := range e {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook is a user-registered HTTP endpoint notified about crawl events.
+// The signing secret is encrypted at rest and only returned when created.
+type Webhook struct {
+	gorm.Model
+	UserID      uint          `json:"userId" gorm:"index;not null"`
+	URL         string        `json:"url" gorm:"type:varchar(2048);not null"`
+	Description string        `json:"description" gorm:"type:varchar(255)"`
+	Events      WebhookEvents `json:"events" gorm:"type:json"`
+	Secret      Secret        `json:"-" gorm:"type:text;not null"`
+	Active      bool          `json:"active" gorm:"default:true"`
+}
+
+// WebhookCreateRequest represents the data needed to register a webhook
+type WebhookCreateRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	Description string   `json:"description" binding:"max=255"`
+	Events      []string `json:"events" binding:"required,min=1,dive,oneof=crawl_completed crawl_error broken_links_found"`
+	Secret      Secret   `json:"secret" binding:"omitempty,min=16,max=256"` // Generated when empty
+}
+
+// WebhookDelivery is one attempt series to deliver an event to a webhook
+type WebhookDelivery struct {
+	gorm.Model
+	WebhookID     uint       `json:"webhookId" gorm:"index;not null"`
+	Event         string     `json:"event" gorm:"type:varchar(50);not null"`
+	Payload       string     `json:"payload" gorm:"type:mediumtext"` // Signed JSON body
+	Status        string     `json:"status" gorm:"type:enum('pending','succeeded','failed');default:'pending';not null;index"`
+	Attempts      int        `json:"attempts" gorm:"default:0"`
+	ResponseCode  int        `json:"responseCode" gorm:"default:0"`
+	LastError     string     `json:"lastError,omitempty" gorm:"type:text"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt" gorm:"index;default:null"`
+	DeliveredAt   *time.Time `json:"deliveredAt" gorm:"default:null"`
+	ReplayOf      *uint      `json:"replayOf,omitempty" gorm:"default:null"` // Original delivery when replayed
+}
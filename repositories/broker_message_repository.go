@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"sykell-challenge/backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type BrokerMessageRepository struct {
+	db *gorm.DB
+}
+
+func NewBrokerMessageRepository(db *gorm.DB) *BrokerMessageRepository {
+	return &BrokerMessageRepository{db: db}
+}
+
+func (r *BrokerMessageRepository) Create(message *models.BrokerMessage) error {
+	return r.db.Create(message).Error
+}
+
+// GetAfter returns messages with an ID above afterID or in extraIDs, oldest first
+func (r *BrokerMessageRepository) GetAfter(afterID uint, extraIDs []uint, limit int) ([]models.BrokerMessage, error) {
+	var messages []models.BrokerMessage
+	query := r.db.Where("id > ?", afterID)
+	if len(extraIDs) > 0 {
+		query = r.db.Where("id > ? OR id IN ?", afterID, extraIDs)
+	}
+	err := query.Order("id ASC").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+// LatestID returns the ID of the newest message, or 0 if there are none
+func (r *BrokerMessageRepository) LatestID() (uint, error) {
+	var id uint
+	err := r.db.Model(&models.BrokerMessage{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+// DeleteOlderThan removes messages every node has had time to read
+func (r *BrokerMessageRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.BrokerMessage{})
+	return result.RowsAffected, result.Error
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"sykell-challenge/backend/models"
 	"time"
 
@@ -15,6 +16,11 @@ func NewCrawlJobRepository(db *gorm.DB) *CrawlJobRepository {
 	return &CrawlJobRepository{db: db}
 }
 
+// WithContext returns a repository whose queries run with ctx, placing them in its trace
+func (r *CrawlJobRepository) WithContext(ctx context.Context) *CrawlJobRepository {
+	return &CrawlJobRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *CrawlJobRepository) Create(job *models.CrawlJob) error {
 	return r.db.Create(job).Error
 }
@@ -37,10 +43,76 @@ func (r *CrawlJobRepository) UpdateStatus(jobID string, status string) error {
 	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Update("status", status).Error
 }
 
+// TransitionStatus moves a job from one status to another; it reports false
+// if the job no longer had the expected status
+func (r *CrawlJobRepository) TransitionStatus(jobID string, from string, to string) (bool, error) {
+	result := r.db.Model(&models.CrawlJob{}).Where("id = ? AND status = ?", jobID, from).Update("status", to)
+	return result.RowsAffected == 1, result.Error
+}
+
 func (r *CrawlJobRepository) UpdateProgress(jobID string, progress int) error {
 	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Update("progress", progress).Error
 }
 
+func (r *CrawlJobRepository) UpdateAttempts(jobID string, attempts int) error {
+	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Update("attempts", attempts).Error
+}
+
+func (r *CrawlJobRepository) UpdateLastError(jobID string, lastError string) error {
+	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Update("last_error", lastError).Error
+}
+
+// ClearCheckpoint removes the saved state of a previously paused job
+func (r *CrawlJobRepository) ClearCheckpoint(jobID string) error {
+	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Update("checkpoint", nil).Error
+}
+
+// UpdateTraceParent sets the trace the job's next run continues; empty starts a new one
+func (r *CrawlJobRepository) UpdateTraceParent(jobID string, traceParent string) error {
+	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Update("trace_parent", traceParent).Error
+}
+
+func (r *CrawlJobRepository) UpdatePriority(jobID string, priority string) error {
+	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Update("priority", priority).Error
+}
+
+// UpdateProgressCounts stores the progress percentage and counters of a running job
+func (r *CrawlJobRepository) UpdateProgressCounts(jobID string, progress models.CrawlJob) error {
+	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).
+		Select("progress", "pages_fetched", "links_discovered", "links_checked", "broken_links", "links_remaining", "eta_seconds").
+		Updates(&progress).Error
+}
+
+// AssignWorker records which node runs a queued job; an empty workerID lets any worker claim it
+func (r *CrawlJobRepository) AssignWorker(jobID string, workerID string) error {
+	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Update("worker_id", workerID).Error
+}
+
+// GetUnclaimedJobIDs returns queued jobs no worker has claimed yet, oldest first
+func (r *CrawlJobRepository) GetUnclaimedJobIDs(limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.CrawlJob{}).Where("status = ? AND worker_id = ?", "queued", "").
+		Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ClaimJob assigns an unclaimed queued job to a worker; it reports false if
+// another worker was faster
+func (r *CrawlJobRepository) ClaimJob(jobID uint, workerID string) (bool, error) {
+	result := r.db.Model(&models.CrawlJob{}).
+		Where("id = ? AND status = ? AND worker_id = ?", jobID, "queued", "").
+		Update("worker_id", workerID)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReleaseQueuedJobs hands a worker's queued jobs back so that other workers can claim them
+func (r *CrawlJobRepository) ReleaseQueuedJobs(workerID string) (int64, error) {
+	result := r.db.Model(&models.CrawlJob{}).
+		Where("status = ? AND worker_id = ?", "queued", workerID).
+		Update("worker_id", "")
+	return result.RowsAffected, result.Error
+}
+
 func (r *CrawlJobRepository) GetActiveJobs() ([]models.CrawlJob, error) {
 	var jobs []models.CrawlJob
 	err := r.db.Where("status IN ?", []string{"queued", "running"}).Find(&jobs).Error
@@ -65,8 +137,160 @@ func (r *CrawlJobRepository) GetJobHistory() ([]models.CrawlJob, error) {
 	return jobs, err
 }
 
-func (r *CrawlJobRepository) DeleteOldJobs(olderThan time.Time) error {
-	return r.db.Where("created_at < ? AND status NOT IN ?", olderThan, []string{"running", "queued"}).Delete(&models.CrawlJob{}).Error
+// CrawlJobQueryParams for filtering the job history
+type CrawlJobQueryParams struct {
+	Page   int        `form:"page,default=1"`
+	Limit  int        `form:"limit,default=10"`
+	Status string     `form:"status"`
+	URLID  *uint      `form:"url_id"`
+	UserID *uint      `form:"-"`                             // Set from the authenticated user
+	From   *time.Time `form:"from" time_format:"2006-01-02"` // Inclusive start date
+	To     *time.Time `form:"to" time_format:"2006-01-02"`   // Inclusive end date
+}
+
+// CrawlJobStats aggregates outcomes and durations over a filtered job history
+type CrawlJobStats struct {
+	StatusCounts       map[string]int64 `json:"statusCounts"`
+	FinishedJobs       int64            `json:"finishedJobs"`
+	AvgDurationSeconds float64          `json:"avgDurationSeconds"`
+	MaxDurationSeconds float64          `json:"maxDurationSeconds"`
+}
+
+// CrawlJobListResponse for paginated job history responses
+type CrawlJobListResponse struct {
+	Data       []models.CrawlJob `json:"data"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+	Stats      CrawlJobStats     `json:"stats"`
+}
+
+// GetJobsWithParams returns the newest jobs first with pagination and filtering,
+// plus statistics over all jobs matching the filters
+func (r *CrawlJobRepository) GetJobsWithParams(params CrawlJobQueryParams) (*CrawlJobListResponse, error) {
+	var jobs []models.CrawlJob
+	var total int64
+
+	filtered := func() *gorm.DB {
+		query := r.db.Model(&models.CrawlJob{})
+		if params.Status != "" {
+			query = query.Where("status = ?", params.Status)
+		}
+		if params.URLID != nil {
+			query = query.Where("url_id = ?", *params.URLID)
+		}
+		if params.UserID != nil {
+			query = query.Where("user_id = ?", *params.UserID)
+		}
+		if params.From != nil {
+			query = query.Where("created_at >= ?", *params.From)
+		}
+		if params.To != nil {
+			query = query.Where("created_at < ?", params.To.AddDate(0, 0, 1))
+		}
+		return query
+	}
+
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	if err := filtered().Order("created_at DESC").Offset(offset).Limit(params.Limit).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	stats, err := r.getJobStats(filtered)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(params.Limit) - 1) / int64(params.Limit))
+
+	return &CrawlJobListResponse{
+		Data:       jobs,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+		Stats:      *stats,
+	}, nil
+}
+
+// getJobStats counts jobs per status and measures the duration of finished ones
+func (r *CrawlJobRepository) getJobStats(filtered func() *gorm.DB) (*CrawlJobStats, error) {
+	stats := &CrawlJobStats{StatusCounts: map[string]int64{}}
+
+	var counts []struct {
+		Status string
+		Count  int64
+	}
+	if err := filtered().Select("status, COUNT(*) AS count").Group("status").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		stats.StatusCounts[c.Status] = c.Count
+	}
+
+	var durations struct {
+		Finished int64
+		Avg      *float64
+		Max      *float64
+	}
+	err := filtered().
+		Select("COUNT(*) AS finished, AVG(TIMESTAMPDIFF(SECOND, started_at, completed_at)) AS avg, MAX(TIMESTAMPDIFF(SECOND, started_at, completed_at)) AS max").
+		Where("started_at IS NOT NULL AND completed_at IS NOT NULL").
+		Scan(&durations).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.FinishedJobs = durations.Finished
+	if durations.Avg != nil {
+		stats.AvgDurationSeconds = *durations.Avg
+	}
+	if durations.Max != nil {
+		stats.MaxDurationSeconds = *durations.Max
+	}
+
+	return stats, nil
+}
+
+// DeleteOldJobs soft deletes (archives) finished jobs created before olderThan
+func (r *CrawlJobRepository) DeleteOldJobs(olderThan time.Time) (int64, error) {
+	result := r.db.Where("created_at < ? AND status NOT IN ?", olderThan, []string{"running", "queued"}).Delete(&models.CrawlJob{})
+	return result.RowsAffected, result.Error
+}
+
+// PurgeOldJobs permanently deletes finished jobs created before olderThan, including archived ones
+func (r *CrawlJobRepository) PurgeOldJobs(olderThan time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("created_at < ? AND status NOT IN ?", olderThan, []string{"running", "queued"}).Delete(&models.CrawlJob{})
+	return result.RowsAffected, result.Error
+}
+
+// GetStaleJobs returns queued or running jobs that have not been updated since before
+func (r *CrawlJobRepository) GetStaleJobs(before time.Time) ([]models.CrawlJob, error) {
+	var jobs []models.CrawlJob
+	err := r.db.Where("status IN ? AND updated_at < ?", []string{"queued", "running"}, before).Find(&jobs).Error
+	return jobs, err
+}
+
+// TouchJobs marks queued or running jobs as updated now, so they do not look stale
+func (r *CrawlJobRepository) TouchJobs(jobIDs []string) error {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.CrawlJob{}).Where("id IN ? AND status IN ?", jobIDs, []string{"queued", "running"}).
+		Update("updated_at", time.Now()).Error
+}
+
+// MarkFailed moves a job to the error state with the given reason
+func (r *CrawlJobRepository) MarkFailed(jobID string, errorMsg string) error {
+	return r.db.Model(&models.CrawlJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":       "error",
+		"error_msg":    errorMsg,
+		"completed_at": time.Now(),
+	}).Error
 }
 
 func (r *CrawlJobRepository) GetJobsByURLID(urlID uint) ([]models.CrawlJob, error) {
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"sykell-challenge/backend/models"
+
+	"gorm.io/gorm"
+)
+
+type CredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewCredentialRepository(db *gorm.DB) *CredentialRepository {
+	return &CredentialRepository{db: db}
+}
+
+// Create stores a new credential; the password is encrypted by models.Secret
+func (r *CredentialRepository) Create(credential *models.Credential) error {
+	return r.db.Create(credential).Error
+}
+
+// GetByID retrieves a credential by its ID
+func (r *CredentialRepository) GetByID(id uint) (*models.Credential, error) {
+	var credential models.Credential
+	err := r.db.First(&credential, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// GetByIDForUser retrieves a credential only if it belongs to the given user
+func (r *CredentialRepository) GetByIDForUser(id, userID uint) (*models.Credential, error) {
+	var credential models.Credential
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// GetByUserID retrieves all credentials owned by a user
+func (r *CredentialRepository) GetByUserID(userID uint) ([]models.Credential, error) {
+	var credentials []models.Credential
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&credentials).Error
+	return credentials, err
+}
+
+// Delete soft deletes a credential owned by the given user
+func (r *CredentialRepository) Delete(id, userID uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Credential{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -1,9 +1,9 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"sykell-challenge/backend/models"
 
@@ -18,6 +18,11 @@ func NewURLRepository(db *gorm.DB) *URLRepository {
 	return &URLRepository{db: db}
 }
 
+// WithContext returns a repository whose queries run with ctx, placing them in its trace
+func (r *URLRepository) WithContext(ctx context.Context) *URLRepository {
+	return &URLRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *URLRepository) Create(url *models.URL) error {
 	return r.db.Create(url).Error
 }
@@ -44,8 +49,6 @@ func (r *URLRepository) GetByStatus(status string) ([]models.URL, error) {
 }
 
 func (r *URLRepository) Update(url *models.URL) error {
-	log.Printf("Updating URL: %+v", url)
-
 	existing, err := r.GetByURL(url.URL)
 	if err != nil {
 		return err
@@ -56,8 +59,6 @@ func (r *URLRepository) Update(url *models.URL) error {
 	}
 
 	*url = *existing // assign updated model to the incoming model
-
-	log.Printf("Updated URL: %+v", url)
 	return nil
 }
 
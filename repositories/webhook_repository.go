@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"sykell-challenge/backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create stores a new webhook; the secret is encrypted by models.Secret
+func (r *WebhookRepository) Create(webhook *models.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+// GetByID retrieves a webhook by its ID
+func (r *WebhookRepository) GetByID(id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := r.db.First(&webhook, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetByIDForUser retrieves a webhook only if it belongs to the given user
+func (r *WebhookRepository) GetByIDForUser(id, userID uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&webhook).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetByUserID retrieves all webhooks registered by a user
+func (r *WebhookRepository) GetByUserID(userID uint) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// GetSubscribed retrieves a user's active webhooks subscribed to event
+func (r *WebhookRepository) GetSubscribed(userID uint, event string) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	if err := r.db.Where("user_id = ? AND active = ?", userID, true).Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+
+	subscribed := webhooks[:0]
+	for _, webhook := range webhooks {
+		if webhook.Events.Has(event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	return subscribed, nil
+}
+
+// Delete soft deletes a webhook owned by the given user
+func (r *WebhookRepository) Delete(id, userID uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Webhook{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *WebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+// GetDelivery retrieves a delivery of the given webhook
+func (r *WebhookRepository) GetDelivery(id, webhookID uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.Where("id = ? AND webhook_id = ?", id, webhookID).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetDeliveries returns the most recent deliveries of a webhook
+func (r *WebhookRepository) GetDeliveries(webhookID uint, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Where("webhook_id = ?", webhookID).Order("created_at DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery pushes a due delivery's next attempt to until, so that other
+// servers polling at the same time skip it; it reports false if one was faster
+func (r *WebhookRepository) ClaimDelivery(id uint, now, until time.Time) (bool, error) {
+	result := r.db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, "pending", now).
+		Update("next_attempt_at", until)
+	return result.RowsAffected == 1, result.Error
+}
+
+// GetDueDeliveries returns pending deliveries whose next attempt is due
+func (r *WebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", "pending", now).
+		Order("next_attempt_at ASC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
@@ -0,0 +1,79 @@
+package broker
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Broker implementations
+const (
+	KindLocal    = "local"    // In-process only; a single server instance
+	KindDatabase = "database" // Messages are exchanged through the shared database
+)
+
+// Message is a published message as seen by subscribers
+type Message struct {
+	ID     uint // Increases in publish order across the cluster; a late message may arrive after newer ones
+	Topic  string
+	Origin string // Node that published the message
+	Data   json.RawMessage
+}
+
+// Decode unmarshals the message data into v
+func (m Message) Decode(v interface{}) error {
+	return json.Unmarshal(m.Data, v)
+}
+
+// Handler processes a message; handlers must not block for long
+type Handler func(Message)
+
+// Broker passes messages between server instances. Every subscriber of a
+// topic receives its messages, including those published by its own node.
+type Broker interface {
+	Publish(topic string, data interface{}) error
+	Subscribe(topic string, handler Handler)
+	Start()
+	Stop()
+}
+
+// Config holds broker configuration
+type Config struct {
+	Kind         string        `yaml:"kind"`         // KindLocal or KindDatabase
+	PollInterval time.Duration `yaml:"pollInterval"` // How often the database broker looks for new messages
+	Retention    time.Duration `yaml:"retention"`    // How long the database broker keeps messages
+}
+
+// DefaultConfig returns the default broker configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Kind:         KindLocal,
+		PollInterval: time.Second,
+		Retention:    10 * time.Minute,
+	}
+}
+
+var globalBroker Broker = NewLocalBroker("local")
+
+// Init creates the global broker for this node
+func Init(config *Config, nodeID string, db *gorm.DB) Broker {
+	if config.Kind == KindDatabase {
+		globalBroker = NewDatabaseBroker(config, nodeID, db)
+	} else {
+		globalBroker = NewLocalBroker(nodeID)
+	}
+
+	slog.Info("Message broker initialized", "broker", config.Kind, "node_id", nodeID)
+	return globalBroker
+}
+
+// Get returns the global broker; an in-process broker until Init is called
+func Get() Broker {
+	return globalBroker
+}
+
+func encode(data interface{}) (json.RawMessage, error) {
+	return json.Marshal(data)
+}
@@ -0,0 +1,167 @@
+package broker
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	pollBatch = 500 // Maximum number of messages read per poll
+	gapWait   = 5 * time.Second
+)
+
+// DatabaseBroker exchanges messages through the broker_messages table. Every
+// node polls for messages newer than the last one it has seen. IDs can become
+// visible out of order when inserts commit concurrently, so skipped IDs are
+// looked for again for a few seconds before they are given up.
+type DatabaseBroker struct {
+	config   *Config
+	nodeID   string
+	repo     *repositories.BrokerMessageRepository
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	lastID   uint
+	gaps     map[uint]time.Time // Skipped IDs and when to stop waiting for them
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewDatabaseBroker(config *Config, nodeID string, db *gorm.DB) *DatabaseBroker {
+	return &DatabaseBroker{
+		config:   config,
+		nodeID:   nodeID,
+		repo:     repositories.NewBrokerMessageRepository(db),
+		handlers: make(map[string][]Handler),
+		gaps:     make(map[uint]time.Time),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (b *DatabaseBroker) Publish(topic string, data interface{}) error {
+	encoded, err := encode(data)
+	if err != nil {
+		return err
+	}
+
+	return b.repo.Create(&models.BrokerMessage{
+		Topic:  topic,
+		Origin: b.nodeID,
+		Data:   string(encoded),
+	})
+}
+
+func (b *DatabaseBroker) Subscribe(topic string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], handler)
+}
+
+// Start delivers new messages in the background until Stop is called.
+// Messages published before Start are not delivered.
+func (b *DatabaseBroker) Start() {
+	lastID, err := b.repo.LatestID()
+	if err != nil {
+		slog.Error("Failed to read latest broker message", "error", err)
+	}
+	b.lastID = lastID
+
+	go func() {
+		defer close(b.done)
+
+		ticker := time.NewTicker(b.config.PollInterval)
+		defer ticker.Stop()
+
+		lastCleanup := time.Now()
+		for {
+			select {
+			case <-ticker.C:
+			case <-b.stop:
+				return
+			}
+
+			b.poll()
+
+			if time.Since(lastCleanup) >= b.config.Retention {
+				b.cleanup()
+				lastCleanup = time.Now()
+			}
+		}
+	}()
+}
+
+// Stop stops polling and waits for the current poll to finish
+func (b *DatabaseBroker) Stop() {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+	})
+	<-b.done
+}
+
+func (b *DatabaseBroker) poll() {
+	now := time.Now()
+	gapIDs := make([]uint, 0, len(b.gaps))
+	for id, deadline := range b.gaps {
+		if now.After(deadline) {
+			delete(b.gaps, id)
+		} else {
+			gapIDs = append(gapIDs, id)
+		}
+	}
+
+	for {
+		messages, err := b.repo.GetAfter(b.lastID, gapIDs, pollBatch)
+		if err != nil {
+			slog.Error("Failed to poll broker messages", "error", err)
+			return
+		}
+		gapIDs = nil
+
+		for _, message := range messages {
+			if message.ID > b.lastID {
+				// Large jumps come from the auto-increment, not from pending inserts
+				if message.ID-b.lastID <= pollBatch {
+					for id := b.lastID + 1; id < message.ID; id++ {
+						b.gaps[id] = now.Add(gapWait)
+					}
+				}
+				b.lastID = message.ID
+			} else {
+				delete(b.gaps, message.ID)
+			}
+			b.dispatch(Message{ID: message.ID, Topic: message.Topic, Origin: message.Origin, Data: []byte(message.Data)})
+		}
+
+		if len(messages) < pollBatch {
+			return
+		}
+	}
+}
+
+func (b *DatabaseBroker) dispatch(message Message) {
+	b.mu.RLock()
+	handlers := b.handlers[message.Topic]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(message)
+	}
+}
+
+func (b *DatabaseBroker) cleanup() {
+	deleted, err := b.repo.DeleteOlderThan(time.Now().Add(-b.config.Retention))
+	if err != nil {
+		slog.Error("Failed to clean up broker messages", "error", err)
+		return
+	}
+	if deleted > 0 {
+		slog.Debug("Deleted old broker messages", "count", deleted)
+	}
+}
@@ -0,0 +1,48 @@
+package broker
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// LocalBroker delivers messages synchronously within the process
+type LocalBroker struct {
+	nodeID   string
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	lastID   atomic.Uint64
+}
+
+func NewLocalBroker(nodeID string) *LocalBroker {
+	return &LocalBroker{
+		nodeID:   nodeID,
+		handlers: make(map[string][]Handler),
+	}
+}
+
+func (b *LocalBroker) Publish(topic string, data interface{}) error {
+	encoded, err := encode(data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[topic]
+	b.mu.RUnlock()
+
+	message := Message{ID: uint(b.lastID.Add(1)), Topic: topic, Origin: b.nodeID, Data: encoded}
+	for _, handler := range handlers {
+		handler(message)
+	}
+	return nil
+}
+
+func (b *LocalBroker) Subscribe(topic string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], handler)
+}
+
+func (b *LocalBroker) Start() {}
+
+func (b *LocalBroker) Stop() {}
@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Run modes
+const (
+	ModeAll    = "all"    // API and workers in one process
+	ModeAPI    = "api"    // HTTP API and Socket.IO only; crawls run on worker nodes
+	ModeWorker = "worker" // Runs crawls only
+)
+
+// Config describes this server instance
+type Config struct {
+	Mode          string        `yaml:"mode"`          // ModeAll, ModeAPI or ModeWorker
+	NodeID        string        `yaml:"nodeId"`        // Unique per instance; identifies the node that claimed a job
+	ClaimInterval time.Duration `yaml:"claimInterval"` // How often workers look for unclaimed queued jobs
+}
+
+// DefaultConfig returns the default node configuration; the node ID is
+// derived from the host name and process ID
+func DefaultConfig() *Config {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "node"
+	}
+
+	return &Config{
+		Mode:          ModeAll,
+		NodeID:        fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		ClaimInterval: 2 * time.Second,
+	}
+}
+
+// RunsAPI reports whether this node serves HTTP and Socket.IO clients
+func (c *Config) RunsAPI() bool {
+	return c.Mode != ModeWorker
+}
+
+// RunsWorkers reports whether this node executes crawls
+func (c *Config) RunsWorkers() bool {
+	return c.Mode != ModeAPI
+}
+
+var nodeID = "local"
+
+// Init records this node's identity for the rest of the process
+func Init(config *Config) {
+	nodeID = config.NodeID
+	slog.Info("Starting node", "node_id", config.NodeID, "mode", config.Mode)
+}
+
+// NodeID returns this node's identity
+func NodeID() string {
+	return nodeID
+}
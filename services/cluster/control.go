@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"log/slog"
+
+	"sykell-challenge/backend/services/broker"
+	"sykell-challenge/backend/services/taskq"
+	crawlUtils "sykell-challenge/backend/utils/crawl"
+)
+
+// controlTopic carries job control requests to the node that holds the job
+const controlTopic = "job_control"
+
+// Job control actions
+const (
+	actionCancel   = "cancel"
+	actionPause    = "pause"
+	actionPriority = "priority"
+	actionResize   = "resize"
+)
+
+type controlMessage struct {
+	Action   string `json:"action"`
+	JobID    string `json:"jobId"`
+	Priority int    `json:"priority,omitempty"`
+	Workers  int    `json:"workers,omitempty"`
+}
+
+// CancelJob stops the job on whichever node has it queued or running
+func CancelJob(jobID string) error {
+	return broker.Get().Publish(controlTopic, controlMessage{Action: actionCancel, JobID: jobID})
+}
+
+// PauseJob pauses the job on whichever node has it queued or running; a
+// running job saves a checkpoint first
+func PauseJob(jobID string) error {
+	return broker.Get().Publish(controlTopic, controlMessage{Action: actionPause, JobID: jobID})
+}
+
+// SetJobPriority reorders the job in the queue of the node that holds it
+func SetJobPriority(jobID string, priority int) error {
+	return broker.Get().Publish(controlTopic, controlMessage{Action: actionPriority, JobID: jobID, Priority: priority})
+}
+
+// ResizeWorkers changes the worker count of every worker node
+func ResizeWorkers(workers int) error {
+	return broker.Get().Publish(controlTopic, controlMessage{Action: actionResize, Workers: workers})
+}
+
+// HandleJobControl applies control requests to the jobs of this node's task queue
+func HandleJobControl() {
+	broker.Get().Subscribe(controlTopic, func(message broker.Message) {
+		var request controlMessage
+		if err := message.Decode(&request); err != nil {
+			slog.Warn("Invalid job control message", "origin", message.Origin, "error", err)
+			return
+		}
+
+		switch request.Action {
+		case actionCancel:
+			if taskq.RemoveQueuedJob(request.JobID) {
+				slog.Info("Removed queued job", "job_id", request.JobID)
+			} else if taskq.CancelJob(request.JobID) {
+				slog.Info("Cancelled running job", "job_id", request.JobID)
+			}
+
+		case actionPause:
+			if taskq.RemoveQueuedJob(request.JobID) {
+				slog.Info("Removed paused job from queue", "job_id", request.JobID)
+			} else if taskq.CancelJobWithCause(request.JobID, crawlUtils.ErrPaused) {
+				slog.Info("Pausing running job", "job_id", request.JobID)
+			}
+
+		case actionPriority:
+			taskq.SetJobPriority(request.JobID, request.Priority)
+
+		case actionResize:
+			if taskq.WorkerLimit() == request.Workers {
+				return
+			}
+			if err := taskq.Resize(request.Workers); err != nil {
+				slog.Error("Failed to resize task queue", "workers", request.Workers, "error", err)
+			}
+		}
+	})
+}
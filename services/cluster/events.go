@@ -0,0 +1,33 @@
+package cluster
+
+import (
+	"log/slog"
+
+	"sykell-challenge/backend/services/broker"
+	"sykell-challenge/backend/services/events"
+)
+
+// eventsTopic carries crawl events to the other nodes
+const eventsTopic = "crawl_events"
+
+// BridgeEvents shares crawl events between nodes: local events are published
+// to the broker, and the history of every node, this one included, is fed
+// from the broker. Events are numbered with their broker message IDs, so a
+// client can resume its Socket.IO or SSE stream on any node. Webhooks, audit
+// logging and metrics only run on the node where an event happened.
+func BridgeEvents(history *events.History) {
+	events.Subscribe(events.SinkFunc(func(event events.Event) {
+		if err := broker.Get().Publish(eventsTopic, event); err != nil {
+			slog.Error("Failed to share crawl event", "event", event.Type, "error", err)
+		}
+	}))
+
+	broker.Get().Subscribe(eventsTopic, func(message broker.Message) {
+		var event events.Event
+		if err := message.Decode(&event); err != nil {
+			slog.Warn("Invalid crawl event", "origin", message.Origin, "error", err)
+			return
+		}
+		history.Append(uint64(message.ID), event)
+	})
+}
@@ -2,15 +2,19 @@ package crawl
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	crawlUtils "sykell-challenge/backend/utils/crawl"
 	"sykell-challenge/backend/utils/crawl/crawl_manager"
 )
 
 func (ct *CrawlTask) CheckCancelled(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
-		log.Printf("Crawl task cancelled before starting: %s", ct.CrawlJob.URL)
+		if errors.Is(context.Cause(ctx), crawlUtils.ErrPaused) {
+			return ct.HandleCrawlFailure(context.Cause(ctx))
+		}
+		ct.logger.Info("Crawl task cancelled before starting")
 		crawl_manager.BroadcastCancelled(ct.CrawlJob)
 		ct.urlRepo.UpdateStatus(ct.CrawlJob.URLID, "cancelled")
 		ct.jobRepo.UpdateStatus(fmt.Sprint(ct.CrawlJob.ID), "cancelled")
@@ -19,3 +23,24 @@ func (ct *CrawlTask) CheckCancelled(ctx context.Context) error {
 		return nil
 	}
 }
+
+// EnsureNotCancelled guards writes of crawl results: it fails if the task's
+// context ended or the job was cancelled in the database in the meantime
+func (ct *CrawlTask) EnsureNotCancelled(ctx context.Context) error {
+	// A pause that arrives after the crawl finished does not discard its result
+	if !errors.Is(context.Cause(ctx), crawlUtils.ErrPaused) {
+		if err := ct.CheckCancelled(ctx); err != nil {
+			return err
+		}
+	}
+
+	job, err := ct.jobRepo.GetByID(fmt.Sprint(ct.CrawlJob.ID))
+	if err != nil {
+		return err
+	}
+	if job.Status == "cancelled" {
+		ct.logger.Info("Discarding results of cancelled crawl")
+		return context.Canceled
+	}
+	return nil
+}
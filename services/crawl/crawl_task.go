@@ -3,46 +3,97 @@ package crawl
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/logging"
 	"sykell-challenge/backend/models"
 	"sykell-challenge/backend/repositories"
 	"sykell-challenge/backend/services/taskq"
+	"sykell-challenge/backend/services/telemetry"
+	crawlUtils "sykell-challenge/backend/utils/crawl"
 	"sykell-challenge/backend/utils/crawl/crawl_manager"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// crawlDeadlineGrace is how long a task may overrun its duration limit before it is abandoned
+const crawlDeadlineGrace = 30 * time.Second
+
 type CrawlTask struct {
 	CrawlJob     models.CrawlJob
 	urlRepo      *repositories.URLRepository
 	jobRepo      *repositories.CrawlJobRepository
-	crawlManager *crawl_manager.CrawlManager
+	crawlManager *crawl_manager.CrawlManager // Recreated for every attempt
+	retryPolicy  RetryPolicy
+	checkpoint   *models.CrawlCheckpoint // State of the last attempt when it was paused
+	logger       *slog.Logger            // Carries the job's identifiers
 }
 
+// Do runs the crawl as part of the trace of the request that queued it
 func (ct *CrawlTask) Do(ctx context.Context) error {
-	log.Printf("Starting crawl task for URL: %s (ID: %d)", ct.CrawlJob.URL, ct.CrawlJob.URLID)
+	ctx, span := telemetry.Tracer().Start(telemetry.ContextWithTraceParent(ctx, ct.CrawlJob.TraceParent), "crawl.task",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.Int64("crawl.job_id", int64(ct.CrawlJob.ID)),
+			attribute.Int64("crawl.url_id", int64(ct.CrawlJob.URLID)),
+			attribute.String("url.full", ct.CrawlJob.URL),
+		))
+	defer span.End()
+	if spanContext := span.SpanContext(); spanContext.IsValid() {
+		ct.logger = ct.logger.With("trace_id", spanContext.TraceID().String())
+	}
 
-	jobCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	// Queries of the task belong to its span, but still run once the task is cancelled
+	queryCtx := context.WithoutCancel(ctx)
+	ct.urlRepo = ct.urlRepo.WithContext(queryCtx)
+	ct.jobRepo = ct.jobRepo.WithContext(queryCtx)
+
+	err := ct.run(ctx)
+	span.SetAttributes(attribute.String("crawl.status", ct.CrawlJob.Status))
+	telemetry.RecordError(span, err)
+	return err
+}
+
+func (ct *CrawlTask) run(ctx context.Context) error {
+	ct.logger.Info("Starting crawl task")
+
+	// Cancelled with crawlUtils.ErrPaused when the job is paused
+	jobCtx, cancel := context.WithCancelCause(logging.WithLogger(ctx, ct.logger))
+	defer cancel(nil)
 
 	jobId := fmt.Sprint(ct.CrawlJob.ID)
 
-	taskq.RegisterJob(jobId, cancel)
+	// A quick pause and resume can leave the job in the queue twice
+	if !taskq.RegisterJob(jobId, cancel) {
+		ct.logger.Info("Skipping crawl task, the job is already running")
+		return nil
+	}
 	defer taskq.UnregisterJob(jobId)
 
 	if err := ct.CheckCancelled(jobCtx); err != nil {
 		return err
 	}
 
+	// Another node may have cancelled, paused or started the job while it was waiting
+	started, err := ct.jobRepo.TransitionStatus(jobId, "queued", "running")
+	if err != nil {
+		return err
+	}
+	if !started {
+		ct.logger.Info("Skipping crawl task, the job is no longer queued")
+		return nil
+	}
+
 	if err := ct.UpdateUrlStatus("running"); err != nil {
 		return err
 	}
 
-	ct.jobRepo.UpdateStatus(jobId, "running")
 	ct.CrawlJob.Status = "running"
 	now := time.Now()
 	ct.CrawlJob.StartedAt = &now
-	ct.CrawlJob.Progress = 25
+	ct.CrawlJob.Progress = 0
 
 	ct.jobRepo.Update(jobId, &ct.CrawlJob)
 
@@ -52,55 +103,112 @@ func (ct *CrawlTask) Do(ctx context.Context) error {
 		return err
 	}
 
-	crawlDone, crawlErr := ct.RunCrawlAsync()
-
-	crawlData, err := ct.WaitForCrawlResult(jobCtx, crawlDone, crawlErr)
+	crawlData, err := ct.CrawlWithRetries(jobCtx)
 	if err != nil {
 		return err
 	}
 
+	if err := ct.EnsureNotCancelled(jobCtx); err != nil {
+		return err
+	}
+
 	if err := ct.UpdateURLRecord(crawlData.MainData); err != nil {
-		log.Printf("Failed to update URL record: %v", err)
+		ct.logger.Error("Failed to update URL record", "error", err)
 		crawl_manager.BroadcastError(ct.CrawlJob, fmt.Sprintf("Failed to save crawl results: %v", err))
 		return err
 	}
 
-	ct.CrawlJob.Status = "completed"
+	ct.CrawlJob.Status = crawlData.Outcome
+	if ct.CrawlJob.Status == "" {
+		ct.CrawlJob.Status = crawlUtils.OutcomeCompleted
+	}
+	ct.CrawlJob.ErrorMsg = crawlData.OutcomeReason
 	now = time.Now()
 	ct.CrawlJob.CompletedAt = &now
 	ct.CrawlJob.Progress = 100 // Set progress to 100% on completion
 
 	ct.jobRepo.Update(jobId, &ct.CrawlJob)
+	if ct.CrawlJob.Checkpoint != nil {
+		ct.jobRepo.ClearCheckpoint(jobId)
+		ct.CrawlJob.Checkpoint = nil
+	}
 
 	crawl_manager.BroadcastCompleted(ct.CrawlJob, crawlData)
 
-	log.Printf("Crawl task completed successfully for URL: %s", ct.CrawlJob.URL)
+	ct.logger.Info("Crawl task finished", "status", ct.CrawlJob.Status)
 	return nil
 }
 
-func CreateCrawlTask(url string, urlID uint) *CrawlTask {
+// JobInfo identifies the task to the fair-share scheduler
+func (ct *CrawlTask) JobInfo() taskq.JobInfo {
+	return taskq.JobInfo{
+		JobID:    fmt.Sprint(ct.CrawlJob.ID),
+		UserID:   ct.CrawlJob.UserID,
+		Priority: models.PriorityRank(ct.CrawlJob.Priority),
+	}
+}
+
+// CreateCrawlTask creates a job for the URL; its logs keep the identifiers of
+// the logger in ctx, such as the request ID
+func CreateCrawlTask(ctx context.Context, url string, urlID uint, userID uint, priority string, options models.CrawlOptions) *CrawlTask {
 	db := db.GetDB()
 	urlRepo := repositories.NewURLRepository(db)
 	jobsRepo := repositories.NewCrawlJobRepository(db)
-	crawlManager := crawl_manager.InitializeCrawlManager(url)
-
 	startedAt := time.Now()
 
 	crawlJob := models.CrawlJob{
-		URL:       url,
-		URLID:     urlID,
-		Status:    "queued",
-		StartedAt: &startedAt,
+		URL:         url,
+		URLID:       urlID,
+		UserID:      userID,
+		Status:      "queued",
+		Priority:    priority,
+		StartedAt:   &startedAt,
+		Options:     options,
+		TraceParent: telemetry.TraceParent(ctx),
+	}
+
+	jobsRepo.WithContext(ctx).Create(&crawlJob)
+
+	crawl_manager.BroadcastJobQueued(crawlJob)
+
+	return &CrawlTask{
+		CrawlJob:    crawlJob,
+		urlRepo:     urlRepo,
+		jobRepo:     jobsRepo,
+		retryPolicy: retryPolicy,
+		logger:      jobLogger(logging.FromContext(ctx), crawlJob),
 	}
+}
+
+// ResumeCrawlTask requeues a paused job; the crawl continues from the job's checkpoint
+func ResumeCrawlTask(ctx context.Context, job models.CrawlJob) *CrawlTask {
+	task := NewCrawlTask(job)
+	task.logger = jobLogger(logging.FromContext(ctx), job)
 
-	jobsRepo.Create(&crawlJob)
+	task.CrawlJob.Status = "queued"
+	task.CrawlJob.TraceParent = telemetry.TraceParent(ctx)
+	task.jobRepo.UpdateStatus(fmt.Sprint(job.ID), "queued")
+	task.jobRepo.UpdateTraceParent(fmt.Sprint(job.ID), task.CrawlJob.TraceParent)
 
-	crawl_manager.BroadcastJobQueued(fmt.Sprintf("%d", crawlJob.ID), url, urlID)
+	crawl_manager.BroadcastJobQueued(task.CrawlJob)
+
+	return task
+}
+
+// NewCrawlTask creates the task for an existing job, e.g. one claimed by a worker
+func NewCrawlTask(job models.CrawlJob) *CrawlTask {
+	db := db.GetDB()
 
 	return &CrawlTask{
-		CrawlJob:     crawlJob,
-		urlRepo:      urlRepo,
-		jobRepo:      jobsRepo,
-		crawlManager: crawlManager,
+		CrawlJob:    job,
+		urlRepo:     repositories.NewURLRepository(db),
+		jobRepo:     repositories.NewCrawlJobRepository(db),
+		retryPolicy: retryPolicy,
+		logger:      jobLogger(slog.Default(), job),
 	}
 }
+
+// jobLogger adds the job's identifiers to logger
+func jobLogger(logger *slog.Logger, job models.CrawlJob) *slog.Logger {
+	return logger.With("job_id", job.ID, "url_id", job.URLID, "user_id", job.UserID, "url", job.URL)
+}
@@ -0,0 +1,130 @@
+package crawl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/repositories"
+	"sykell-challenge/backend/services/cluster"
+	"sykell-challenge/backend/services/taskq"
+)
+
+// Submit hands a queued task to a worker. A node that runs workers claims
+// and queues it itself; otherwise the job stays unclaimed in the database
+// until a worker node picks it up.
+func Submit(task *CrawlTask) error {
+	jobID := fmt.Sprint(task.CrawlJob.ID)
+
+	if !taskq.Started() {
+		return task.jobRepo.AssignWorker(jobID, "")
+	}
+
+	if err := task.jobRepo.AssignWorker(jobID, cluster.NodeID()); err != nil {
+		return err
+	}
+	_, err := taskq.EnqueueTask(context.Background(), task)
+	return err
+}
+
+// Claimer moves unclaimed queued jobs from the database into this node's task queue
+type Claimer struct {
+	interval time.Duration
+	jobRepo  *repositories.CrawlJobRepository
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewClaimer(config *cluster.Config) *Claimer {
+	return &Claimer{
+		interval: config.ClaimInterval,
+		jobRepo:  repositories.NewCrawlJobRepository(db.GetDB()),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start claims jobs in the background until Stop is called. Jobs this node
+// claimed before a restart are released first.
+func (c *Claimer) Start() {
+	c.release()
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.claim()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops claiming and hands jobs still waiting in the local queue back
+// to the other workers; call it after the task queue has shut down
+func (c *Claimer) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	<-c.done
+	c.release()
+}
+
+func (c *Claimer) claim() {
+	// Keep about one waiting job per worker in the local queue
+	free := taskq.WorkerLimit() - taskq.QueueLength()
+	if free <= 0 {
+		return
+	}
+
+	ids, err := c.jobRepo.GetUnclaimedJobIDs(free)
+	if err != nil {
+		slog.Error("Failed to look for unclaimed jobs", "error", err)
+		return
+	}
+
+	for _, id := range ids {
+		claimed, err := c.jobRepo.ClaimJob(id, cluster.NodeID())
+		if err != nil {
+			slog.Error("Failed to claim job", "job_id", id, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		job, err := c.jobRepo.GetByID(fmt.Sprint(id))
+		if err != nil {
+			slog.Error("Failed to load claimed job", "job_id", id, "error", err)
+			continue
+		}
+
+		if _, err := taskq.EnqueueTask(context.Background(), NewCrawlTask(*job)); err != nil {
+			slog.Error("Failed to enqueue claimed job", "job_id", id, "error", err)
+			c.jobRepo.AssignWorker(fmt.Sprint(id), "")
+			continue
+		}
+		slog.Info("Claimed job", "job_id", id)
+	}
+}
+
+func (c *Claimer) release() {
+	released, err := c.jobRepo.ReleaseQueuedJobs(cluster.NodeID())
+	if err != nil {
+		slog.Error("Failed to release queued jobs", "error", err)
+		return
+	}
+	if released > 0 {
+		slog.Info("Released queued jobs", "count", released)
+	}
+}
@@ -0,0 +1,151 @@
+package crawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sykell-challenge/backend/services/telemetry"
+	crawlUtils "sykell-challenge/backend/utils/crawl"
+	"sykell-challenge/backend/utils/crawl/crawl_manager"
+	"sykell-challenge/backend/utils/egress"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RetryPolicy controls how often and how fast failed crawls are retried
+type RetryPolicy struct {
+	MaxAttempts int           `yaml:"maxAttempts"` // Total attempts including the first one
+	BaseDelay   time.Duration `yaml:"baseDelay"`   // Delay before the first retry, doubled on each further retry
+	MaxDelay    time.Duration `yaml:"maxDelay"`    // Upper bound for a single delay
+}
+
+// DefaultRetryPolicy returns the default retry policy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   2 * time.Second,
+		MaxDelay:    30 * time.Second,
+	}
+}
+
+var retryPolicy = DefaultRetryPolicy()
+
+// SetRetryPolicy sets the policy of crawl tasks created from now on
+func SetRetryPolicy(policy RetryPolicy) {
+	retryPolicy = policy
+}
+
+// Backoff returns the delay after the given failed attempt: exponential,
+// capped at MaxDelay, with jitter in the upper half so retries spread out
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// CrawlWithRetries runs crawl attempts until one succeeds, a non-retryable
+// error occurs or the attempts are used up; failures are recorded on the job
+func (ct *CrawlTask) CrawlWithRetries(ctx context.Context) (crawlUtils.CrawlData, error) {
+	jobId := fmt.Sprint(ct.CrawlJob.ID)
+
+	for attempt := 1; ; attempt++ {
+		ct.CrawlJob.Attempts = attempt
+		ct.jobRepo.UpdateAttempts(jobId, attempt)
+
+		crawlData, err := ct.runAttempt(ctx)
+		if err == nil && !isRetryableStatus(crawlData.MainData.StatusCode) {
+			return crawlData, nil
+		}
+
+		retryable := err == nil || isRetryableError(err)
+		if attempt >= ct.retryPolicy.MaxAttempts || !retryable || ctx.Err() != nil {
+			if err == nil {
+				// Out of attempts: keep the last server error page as the result
+				return crawlData, nil
+			}
+			return crawlUtils.CrawlData{}, ct.HandleCrawlFailure(err)
+		}
+
+		reason := fmt.Sprintf("HTTP %d", crawlData.MainData.StatusCode)
+		if err != nil {
+			reason = err.Error()
+		}
+		ct.CrawlJob.LastError = reason
+		ct.jobRepo.UpdateLastError(jobId, reason)
+
+		delay := ct.retryPolicy.Backoff(attempt)
+		ct.logger.Warn("Crawl attempt failed, retrying", "attempt", attempt, "max_attempts", ct.retryPolicy.MaxAttempts, "reason", reason, "delay", delay.String())
+		crawl_manager.BroadcastRetrying(ct.CrawlJob, ct.retryPolicy.MaxAttempts, delay, reason)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return crawlUtils.CrawlData{}, ct.HandleCrawlFailure(context.Cause(ctx))
+		}
+	}
+}
+
+// runAttempt crawls once with a fresh CrawlManager
+func (ct *CrawlTask) runAttempt(ctx context.Context) (crawlData crawlUtils.CrawlData, err error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "crawl.attempt")
+	span.SetAttributes(attribute.Int("crawl.attempt", ct.CrawlJob.Attempts))
+	defer func() {
+		span.SetAttributes(attribute.Int("http.response.status_code", crawlData.MainData.StatusCode))
+		telemetry.RecordError(span, err)
+		span.End()
+	}()
+
+	// Backstop in case the crawl does not stop at its own deadline
+	attemptCtx, cancel := context.WithTimeout(ctx, ct.CrawlJob.Options.Limits.DurationLimit()+crawlDeadlineGrace)
+	defer cancel()
+
+	ct.crawlManager = crawl_manager.InitializeCrawlManager(ct.CrawlJob.URL, ct.CrawlJob.Options)
+	ct.crawlManager.SetJob(ct.CrawlJob)
+	ct.crawlManager.SetLogger(ct.logger.With("attempt", ct.CrawlJob.Attempts))
+	if ct.CrawlJob.Checkpoint != nil {
+		ct.crawlManager.Restore(*ct.CrawlJob.Checkpoint)
+	}
+	ct.checkpoint = nil
+
+	crawlDone, crawlErr := ct.RunCrawlAsync(attemptCtx)
+
+	return ct.WaitForCrawlResult(attemptCtx, crawlDone, crawlErr)
+}
+
+// isRetryableStatus reports whether a response status indicates a transient server problem
+func isRetryableStatus(statusCode int) bool {
+	return statusCode >= 500 || statusCode == http.StatusTooManyRequests || statusCode == http.StatusRequestTimeout
+}
+
+// isRetryableError classifies transient network failures: timeouts, temporary
+// DNS failures and dropped connections
+func isRetryableError(err error) bool {
+	if egress.IsBlocked(err) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, crawlUtils.ErrPaused) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound || dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
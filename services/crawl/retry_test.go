@@ -0,0 +1,77 @@
+package crawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	crawlUtils "sykell-challenge/backend/utils/crawl"
+	"sykell-challenge/backend/utils/egress"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 2 * time.Second, MaxDelay: 30 * time.Second}
+
+	tests := []struct {
+		attempt int
+		delay   time.Duration // Before jitter
+	}{
+		{attempt: 1, delay: 2 * time.Second},
+		{attempt: 2, delay: 4 * time.Second},
+		{attempt: 3, delay: 8 * time.Second},
+		{attempt: 4, delay: 16 * time.Second},
+		{attempt: 5, delay: 30 * time.Second},
+		{attempt: 64, delay: 30 * time.Second}, // Shift overflow falls back to the cap
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint("attempt ", tt.attempt), func(t *testing.T) {
+			for range 100 {
+				got := policy.Backoff(tt.attempt)
+				if got < tt.delay/2 || got > tt.delay {
+					t.Fatalf("Backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.delay/2, tt.delay)
+				}
+			}
+		})
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, want: true},
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: true},
+		{name: "temporary DNS failure", err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}, want: true},
+		{name: "unknown host", err: &net.DNSError{Err: "no such host", IsNotFound: true}, want: false},
+		{name: "blocked by the SSRF guard", err: &egress.BlockedError{Host: "127.0.0.1", Reason: "loopback address"}, want: false},
+		{name: "cancelled", err: context.Canceled, want: false},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: false},
+		{name: "paused", err: crawlUtils.ErrPaused, want: false},
+		{name: "other error", err: errors.New("invalid response"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.want {
+				t.Errorf("isRetryableError(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	for status, want := range map[int]bool{200: false, 404: false, 408: true, 429: true, 500: true, 503: true} {
+		if got := isRetryableStatus(status); got != want {
+			t.Errorf("isRetryableStatus(%d) = %t, want %t", status, got, want)
+		}
+	}
+}
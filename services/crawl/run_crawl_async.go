@@ -1,11 +1,12 @@
 package crawl
 
 import (
+	"context"
 	"fmt"
 	crawlUtils "sykell-challenge/backend/utils/crawl"
 )
 
-func (ct *CrawlTask) RunCrawlAsync() (chan crawlUtils.CrawlData, chan error) {
+func (ct *CrawlTask) RunCrawlAsync(ctx context.Context) (chan crawlUtils.CrawlData, chan error) {
 	crawlDone := make(chan crawlUtils.CrawlData, 1)
 	crawlErr := make(chan error, 1)
 
@@ -15,7 +16,11 @@ func (ct *CrawlTask) RunCrawlAsync() (chan crawlUtils.CrawlData, chan error) {
 				crawlErr <- fmt.Errorf("crawl panic: %v", r)
 			}
 		}()
-		crawlData := ct.crawlManager.Crawl()
+		crawlData, err := ct.crawlManager.Crawl(ctx)
+		if err != nil {
+			crawlErr <- err
+			return
+		}
 		crawlDone <- crawlData
 
 	}()
@@ -2,7 +2,6 @@ package crawl
 
 import (
 	"fmt"
-	"log"
 	"sykell-challenge/backend/models"
 	"sykell-challenge/backend/utils/crawl/crawl_manager"
 )
@@ -10,7 +9,7 @@ import (
 func (ct *CrawlTask) UpdateURLRecord(url models.URL) error {
 	urlRecord, err := ct.urlRepo.GetByID(ct.CrawlJob.URLID)
 	if err != nil {
-		log.Printf("Failed to get URL record: %v", err)
+		ct.logger.Error("Failed to get URL record", "error", err)
 		crawl_manager.BroadcastError(ct.CrawlJob, fmt.Sprintf("Failed to get URL record: %v", err))
 		return err
 	}
@@ -18,14 +17,19 @@ func (ct *CrawlTask) UpdateURLRecord(url models.URL) error {
 	urlRecord.Title = url.Title
 	urlRecord.StatusCode = url.StatusCode
 	urlRecord.HTMLVersion = url.HTMLVersion
+	urlRecord.ContentType = url.ContentType
+	urlRecord.ContentLength = url.ContentLength
 	urlRecord.LoginForm = url.LoginForm
+	urlRecord.Forms = url.Forms
+	urlRecord.AuthRequired = url.AuthRequired
+	urlRecord.AuthScheme = url.AuthScheme
 	urlRecord.Tags = url.Tags
 	urlRecord.Links = url.Links
 	urlRecord.JobId = fmt.Sprint(ct.CrawlJob.ID)
 	urlRecord.Status = "done"
 
 	if err := ct.urlRepo.Update(urlRecord); err != nil {
-		log.Printf("Failed to update URL record: %v", err)
+		ct.logger.Error("Failed to update URL record", "error", err)
 		crawl_manager.BroadcastError(ct.CrawlJob, fmt.Sprintf("Failed to save crawl results: %v", err))
 
 		ct.urlRepo.UpdateStatus(ct.CrawlJob.URLID, "error")
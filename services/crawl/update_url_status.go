@@ -2,13 +2,12 @@ package crawl
 
 import (
 	"fmt"
-	"log"
 	"sykell-challenge/backend/utils/crawl/crawl_manager"
 )
 
 func (ct *CrawlTask) UpdateUrlStatus(status string) error {
 	if err := ct.urlRepo.UpdateStatus(ct.CrawlJob.URLID, status); err != nil {
-		log.Printf("Failed to update URL (%s) status to %s: %v", ct.CrawlJob.URL, status, err)
+		ct.logger.Error("Failed to update URL status", "status", status, "error", err)
 		crawl_manager.BroadcastError(ct.CrawlJob, fmt.Sprintf("Failed to update url status: %v", err))
 		return err
 	}
@@ -18,7 +17,7 @@ func (ct *CrawlTask) UpdateUrlStatus(status string) error {
 func (ct *CrawlTask) UpdateJobStatus(status string) error {
 	jobId := fmt.Sprint(ct.CrawlJob.ID)
 	if err := ct.jobRepo.UpdateStatus(jobId, status); err != nil {
-		log.Printf("Failed to update JOB (%s) status to %s: %v", jobId, status, err)
+		ct.logger.Error("Failed to update job status", "status", status, "error", err)
 		crawl_manager.BroadcastError(ct.CrawlJob, fmt.Sprintf("Failed to update job status: %v", err))
 		return err
 	}
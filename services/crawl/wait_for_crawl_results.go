@@ -2,30 +2,83 @@ package crawl
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"sykell-challenge/backend/models"
 	crawlUtils "sykell-challenge/backend/utils/crawl"
 	"sykell-challenge/backend/utils/crawl/crawl_manager"
+	"time"
 )
 
+// pauseGrace is how long a paused crawl may take to stop before its progress is discarded
+const pauseGrace = 10 * time.Second
+
+// WaitForCrawlResult waits for a crawl attempt to finish, fail, or for ctx to end.
+// When ctx ends because the job is paused, it waits for the crawl to stop and keeps its checkpoint.
 func (ct *CrawlTask) WaitForCrawlResult(ctx context.Context, crawlDone <-chan crawlUtils.CrawlData, crawlErr <-chan error) (crawlUtils.CrawlData, error) {
 	select {
 	case <-ctx.Done():
-		log.Printf("Crawl task cancelled during crawling: %s", ct.CrawlJob.URL)
-		crawl_manager.BroadcastCancelled(ct.CrawlJob)
-		ct.UpdateUrlStatus("cancelled")
-		ct.UpdateJobStatus("cancelled")
-		return crawlUtils.CrawlData{}, ctx.Err()
+		if errors.Is(context.Cause(ctx), crawlUtils.ErrPaused) {
+			select {
+			case <-crawlDone:
+			case <-crawlErr:
+			case <-time.After(pauseGrace):
+				ct.logger.Warn("Crawl did not stop in time, pausing without checkpoint")
+				return crawlUtils.CrawlData{}, context.Cause(ctx)
+			}
+			ct.checkpoint = ct.crawlManager.Checkpoint()
+		}
+		return crawlUtils.CrawlData{}, context.Cause(ctx)
 
 	case err := <-crawlErr:
-		log.Printf("Crawl task failed: %v", err)
-		crawl_manager.BroadcastError(ct.CrawlJob, fmt.Sprintf("Crawl failed: %v", err))
-		ct.UpdateUrlStatus("error")
-		ct.UpdateJobStatus("error")
+		if ctx.Err() != nil {
+			// The crawl stopped because ctx ended
+			if errors.Is(context.Cause(ctx), crawlUtils.ErrPaused) {
+				ct.checkpoint = ct.crawlManager.Checkpoint()
+			}
+			return crawlUtils.CrawlData{}, context.Cause(ctx)
+		}
 		return crawlUtils.CrawlData{}, err
 
 	case crawlData := <-crawlDone:
-		// crawl_manager.BroadcastHalfCompleted(ct.crawlJob, crawlData)
 		return crawlData, nil
 	}
 }
+
+// HandleCrawlFailure records the final state of a job whose crawl did not produce a result
+func (ct *CrawlTask) HandleCrawlFailure(err error) error {
+	jobId := fmt.Sprint(ct.CrawlJob.ID)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		errorMsg := fmt.Sprintf("crawl exceeded the maximum duration of %s", ct.CrawlJob.Options.Limits.DurationLimit())
+		ct.logger.Warn("Crawl task timed out", "limit", ct.CrawlJob.Options.Limits.DurationLimit().String())
+		crawl_manager.BroadcastError(ct.CrawlJob, errorMsg)
+		ct.UpdateUrlStatus("error")
+		ct.jobRepo.Update(jobId, &models.CrawlJob{Status: crawlUtils.OutcomeTimeout, ErrorMsg: errorMsg})
+
+	case errors.Is(err, crawlUtils.ErrPaused):
+		ct.logger.Info("Crawl task paused", "checkpoint", ct.checkpoint != nil)
+		ct.CrawlJob.Status = "paused"
+		if ct.checkpoint != nil {
+			ct.CrawlJob.Checkpoint = ct.checkpoint
+		}
+		ct.jobRepo.Update(jobId, &models.CrawlJob{Status: "paused", Checkpoint: ct.checkpoint})
+		ct.UpdateUrlStatus("paused")
+		crawl_manager.BroadcastPaused(ct.CrawlJob)
+
+	case errors.Is(err, context.Canceled):
+		ct.logger.Info("Crawl task cancelled during crawling")
+		crawl_manager.BroadcastCancelled(ct.CrawlJob)
+		ct.UpdateUrlStatus("cancelled")
+		ct.UpdateJobStatus("cancelled")
+
+	default:
+		ct.logger.Error("Crawl task failed", "error", err)
+		crawl_manager.BroadcastError(ct.CrawlJob, fmt.Sprintf("Crawl failed: %v", err))
+		ct.UpdateUrlStatus("error")
+		ct.jobRepo.Update(jobId, &models.CrawlJob{Status: "error", ErrorMsg: err.Error(), LastError: err.Error()})
+	}
+
+	return err
+}
@@ -0,0 +1,140 @@
+package events
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"sykell-challenge/backend/models"
+)
+
+// Crawl lifecycle event types, also used as Socket.IO event names
+const (
+	CrawlQueued        = "crawl_queued"
+	CrawlStarted       = "crawl_started"
+	CrawlProgress      = "crawl_progress"
+	CrawlHalfCompleted = "crawl_half_completed"
+	CrawlRetrying      = "crawl_retrying"
+	CrawlPaused        = "crawl_paused"
+	CrawlCompleted     = "crawl_completed"
+	CrawlError         = "crawl_error"
+	CrawlCancelled     = "crawl_cancelled"
+)
+
+// Event is a crawl lifecycle event as published by the crawl pipeline
+type Event struct {
+	Type    string    // One of the Crawl* constants
+	UserID  uint      // Owner of the job; 0 when unknown
+	Time    time.Time // Set by Publish when empty
+	Payload Payload   // What subscribers such as Socket.IO clients and webhooks receive
+}
+
+// Payload is the single schema shared by all crawl events; fields that do
+// not apply to an event are left empty
+type Payload struct {
+	Seq         uint64       `json:"seq,omitempty"` // Position in the event history, set once recorded
+	JobID       string       `json:"jobId"`
+	URL         string       `json:"url"`
+	URLID       string       `json:"urlId"`
+	Status      string       `json:"status"`
+	StartedAt   string       `json:"startedAt,omitempty"`
+	CompletedAt string       `json:"completedAt,omitempty"`
+	Progress    int          `json:"progress,omitempty"`
+	Title       string       `json:"title,omitempty"`
+	StatusCode  int          `json:"statusCode,omitempty"`
+	HTMLVersion string       `json:"htmlVersion,omitempty"`
+	LoginForm   bool         `json:"loginForm,omitempty"`
+	Forms       models.Forms `json:"forms,omitempty"`
+	AuthScheme  string       `json:"authScheme,omitempty"`
+	LinksCount  int          `json:"linksCount,omitempty"`
+	TagsCount   int          `json:"tagsCount,omitempty"`
+	Tags        models.Tags  `json:"tags,omitempty"`
+	Links       models.Links `json:"links,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Attempt     int          `json:"attempt,omitempty"`
+	MaxAttempts int          `json:"maxAttempts,omitempty"`
+	RetryIn     float64      `json:"retryInSeconds,omitempty"`
+	*Counts                  // Progress counters, flattened into the payload when set
+}
+
+// Counts are the progress counters of a running crawl
+type Counts struct {
+	PagesFetched    int `json:"pagesFetched"`
+	LinksDiscovered int `json:"linksDiscovered"`
+	LinksChecked    int `json:"linksChecked"`
+	BrokenLinks     int `json:"brokenLinks"`
+	LinksRemaining  int `json:"linksRemaining"`
+	ETASeconds      int `json:"etaSeconds"`
+}
+
+// Sink receives every published event. Sinks run synchronously on the
+// publishing goroutine and must not block for long.
+type Sink interface {
+	Handle(event Event)
+}
+
+// SinkFunc adapts a function to a Sink
+type SinkFunc func(event Event)
+
+func (f SinkFunc) Handle(event Event) {
+	f(event)
+}
+
+// Bus fans events out to its sinks
+type Bus struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds a sink that receives all events published afterwards
+func (b *Bus) Subscribe(sink Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// Publish delivers an event to every sink; a failing sink does not affect the others
+func (b *Bus) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	sinks := b.sinks
+	b.mu.RUnlock()
+
+	for _, sink := range sinks {
+		deliver(sink, event)
+	}
+}
+
+func deliver(sink Sink, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Event sink panicked", "sink", fmt.Sprintf("%T", sink), "event", event.Type, "panic", r)
+		}
+	}()
+	sink.Handle(event)
+}
+
+var defaultBus = NewBus()
+
+// Default returns the process-wide bus used by the crawl pipeline
+func Default() *Bus {
+	return defaultBus
+}
+
+// Subscribe adds a sink to the default bus
+func Subscribe(sink Sink) {
+	defaultBus.Subscribe(sink)
+}
+
+// Publish publishes an event on the default bus
+func Publish(event Event) {
+	defaultBus.Publish(event)
+}
@@ -0,0 +1,216 @@
+package events
+
+import (
+	"slices"
+	"sort"
+	"sync"
+)
+
+// listenerBuffer is how many records a listener may fall behind before it is dropped
+const listenerBuffer = 64
+
+// Record is an event numbered by its position in the history
+type Record struct {
+	Seq uint64
+	Event
+}
+
+// History keeps the most recent events in a ring buffer so that clients which
+// reconnect can catch up from the last sequence number they saw. A single
+// node numbers its events itself; in a cluster every node appends the events
+// with the sequence numbers the broker gave them, so the numbers are the same
+// on every node.
+type History struct {
+	mu        sync.Mutex
+	forwardMu sync.Mutex // Keeps forwards in the order records were added, without holding mu
+	capacity  int
+	records   []Record // Ordered by sequence number
+	lastSeq   uint64
+	floor     uint64 // Events up to this sequence number may be missing
+	listeners map[chan Record]struct{}
+	forwards  []func(Record) // Called for every record, in the order they were added
+	closed    bool
+}
+
+// HistoryConfig holds the event history configuration
+type HistoryConfig struct {
+	Size int `yaml:"size"` // Number of events kept for replay
+}
+
+// DefaultHistoryConfig returns the default event history configuration
+func DefaultHistoryConfig() *HistoryConfig {
+	return &HistoryConfig{Size: 1000}
+}
+
+func NewHistory(capacity int) *History {
+	return &History{
+		capacity:  capacity,
+		listeners: make(map[chan Record]struct{}),
+	}
+}
+
+var history *History
+
+// InitHistory creates the global history. Subscribe it to the default bus
+// to number this node's events, or feed it the cluster's events with Append.
+func InitHistory(config *HistoryConfig) *History {
+	history = NewHistory(config.Size)
+	return history
+}
+
+// GetHistory returns the global history, or nil before InitHistory
+func GetHistory() *History {
+	return history
+}
+
+// Handle numbers the event with the next sequence number, stores it and
+// passes it on to the listeners and forwards
+func (h *History) Handle(event Event) {
+	h.mu.Lock()
+	record, added := h.insert(h.lastSeq+1, event)
+	h.publish(record, added)
+}
+
+// Append stores an event numbered elsewhere and passes it on. Events may
+// arrive out of order; one already stored, or older than the stored range, is dropped.
+func (h *History) Append(seq uint64, event Event) {
+	h.mu.Lock()
+	record, added := h.insert(seq, event)
+	h.publish(record, added)
+}
+
+func (h *History) insert(seq uint64, event Event) (Record, bool) {
+	if h.closed || seq <= h.floor {
+		return Record{}, false
+	}
+	if h.lastSeq == 0 {
+		// Events from before the first one seen are unknown
+		h.floor = seq - 1
+	}
+
+	i := sort.Search(len(h.records), func(i int) bool { return h.records[i].Seq >= seq })
+	if i < len(h.records) && h.records[i].Seq == seq {
+		return Record{}, false
+	}
+
+	record := Record{Seq: seq, Event: event}
+	record.Payload.Seq = record.Seq
+
+	h.records = slices.Insert(h.records, i, record)
+	h.lastSeq = max(h.lastSeq, seq)
+	if evicted := len(h.records) - h.capacity; evicted > 0 {
+		h.floor = h.records[evicted-1].Seq
+		h.records = h.records[evicted:]
+	}
+
+	return record, true
+}
+
+// publish passes a new record on and releases mu, which the caller holds. A
+// listener that has fallen too far behind is closed; it can reconnect and
+// replay from its last sequence number.
+func (h *History) publish(record Record, added bool) {
+	if !added {
+		h.mu.Unlock()
+		return
+	}
+
+	for listener := range h.listeners {
+		select {
+		case listener <- record:
+		default:
+			delete(h.listeners, listener)
+			close(listener)
+		}
+	}
+
+	// Taken before mu is released, so forwards see records in the order they were added
+	h.forwardMu.Lock()
+	defer h.forwardMu.Unlock()
+	forwards := h.forwards
+	h.mu.Unlock()
+
+	for _, forward := range forwards {
+		forward(record)
+	}
+}
+
+// OnRecord registers fn to receive every numbered event, in the order they
+// are added. fn must not block, since it holds up further events.
+func (h *History) OnRecord(fn func(Record)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.forwards = append(h.forwards, fn)
+}
+
+// Listen returns the events after seq followed by a channel of new events.
+// complete is false if some events after seq have already been evicted.
+// The channel is closed when the listener is removed, falls behind or the
+// history is closed; stop must be called once the caller is done.
+func (h *History) Listen(seq uint64) (missed []Record, complete bool, records <-chan Record, stop func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	missed, complete = h.since(seq)
+
+	listener := make(chan Record, listenerBuffer)
+	if h.closed {
+		close(listener)
+		return missed, complete, listener, func() {}
+	}
+	h.listeners[listener] = struct{}{}
+
+	stop = func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.listeners[listener]; ok {
+			delete(h.listeners, listener)
+			close(listener)
+		}
+	}
+
+	return missed, complete, listener, stop
+}
+
+// Since returns the stored events after seq; complete is false if some of
+// them have already been evicted
+func (h *History) Since(seq uint64) (records []Record, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.since(seq)
+}
+
+func (h *History) since(seq uint64) ([]Record, bool) {
+	if seq > h.lastSeq {
+		// Local sequence numbers restart with the process, and a node may
+		// not have received the cluster's latest events yet
+		return append([]Record(nil), h.records...), false
+	}
+
+	var records []Record
+	for _, record := range h.records {
+		if record.Seq > seq {
+			records = append(records, record)
+		}
+	}
+	return records, seq >= h.floor
+}
+
+// LastSeq returns the sequence number of the most recent event
+func (h *History) LastSeq() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastSeq
+}
+
+// Close ends all listeners so that open streams finish during shutdown
+func (h *History) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for listener := range h.listeners {
+		delete(h.listeners, listener)
+		close(listener)
+	}
+}
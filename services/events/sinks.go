@@ -0,0 +1,22 @@
+package events
+
+import (
+	"log/slog"
+)
+
+// AuditSink logs every lifecycle event except progress updates
+type AuditSink struct{}
+
+func (AuditSink) Handle(event Event) {
+	if event.Type == CrawlProgress {
+		return
+	}
+
+	p := event.Payload
+	attrs := []any{"event", event.Type, "job_id", p.JobID, "url_id", p.URLID, "user_id", event.UserID, "status", p.Status}
+	if p.Error != "" {
+		slog.Warn("Crawl event", append(attrs, "error", p.Error)...)
+		return
+	}
+	slog.Info("Crawl event", attrs...)
+}
@@ -0,0 +1,180 @@
+package janitor
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/repositories"
+	"sykell-challenge/backend/services/taskq"
+	"sykell-challenge/backend/utils/crawl/crawl_manager"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// ModeArchive soft deletes old jobs, keeping them in the database
+	ModeArchive = "archive"
+	// ModeDelete permanently removes old jobs
+	ModeDelete = "delete"
+)
+
+// Config holds janitor configuration
+type Config struct {
+	Interval      time.Duration `yaml:"interval"`      // How often the janitor runs
+	RetentionDays int           `yaml:"retentionDays"` // Finished jobs older than this are cleaned up; 0 disables cleanup
+	Mode          string        `yaml:"mode"`          // ModeArchive or ModeDelete
+	StaleTimeout  time.Duration `yaml:"staleTimeout"`  // Queued/running jobs without updates for this long are reaped
+}
+
+// DefaultConfig returns the default janitor configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Interval:      10 * time.Minute,
+		RetentionDays: 30,
+		Mode:          ModeArchive,
+		StaleTimeout:  2 * time.Hour,
+	}
+}
+
+// Janitor periodically reaps stuck crawl jobs and cleans up old ones. It
+// runs on worker nodes, each of which also keeps its own live jobs fresh so
+// that the janitors of other nodes leave them alone.
+type Janitor struct {
+	config   *Config
+	jobRepo  *repositories.CrawlJobRepository
+	urlRepo  *repositories.URLRepository
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewJanitor(config *Config, db *gorm.DB) *Janitor {
+	return &Janitor{
+		config:  config,
+		jobRepo: repositories.NewCrawlJobRepository(db),
+		urlRepo: repositories.NewURLRepository(db),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs the janitor in the background, once immediately and then every Interval
+func (j *Janitor) Start() {
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(j.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			j.RunOnce()
+
+			select {
+			case <-ticker.C:
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+
+	slog.Info("Janitor started", "interval", j.config.Interval.String(), "retention_days", j.config.RetentionDays,
+		"mode", j.config.Mode, "stale_timeout", j.config.StaleTimeout.String())
+}
+
+// Stop stops the janitor and waits for a running pass to finish
+func (j *Janitor) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.stop)
+	})
+	<-j.done
+}
+
+// RunOnce performs a single reaping and cleanup pass
+func (j *Janitor) RunOnce() {
+	j.reapStaleJobs()
+	j.cleanupOldJobs()
+}
+
+// reapStaleJobs marks jobs stuck in queued/running as failed. Jobs of a
+// node that stopped without handing them back are no longer touched and
+// are reaped by the remaining nodes.
+func (j *Janitor) reapStaleJobs() {
+	if err := j.jobRepo.TouchJobs(taskq.LocalJobIDs()); err != nil {
+		slog.Error("Janitor failed to refresh this node's jobs", "error", err)
+		return
+	}
+
+	cutoff := time.Now().Add(-j.config.StaleTimeout)
+
+	jobs, err := j.jobRepo.GetStaleJobs(cutoff)
+	if err != nil {
+		slog.Error("Janitor failed to load stale jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		jobID := fmt.Sprint(job.ID)
+		if taskq.IsJobRunning(jobID) {
+			// Still owned by a live worker in this process
+			continue
+		}
+		if _, queued := taskq.QueuePosition(jobID); queued {
+			// Still waiting in this process's queue
+			continue
+		}
+
+		errorMsg := fmt.Sprintf("job reaped after being %s for more than %s", job.Status, j.config.StaleTimeout)
+		if err := j.jobRepo.MarkFailed(jobID, errorMsg); err != nil {
+			slog.Error("Janitor failed to reap job", "job_id", jobID, "error", err)
+			continue
+		}
+
+		j.resetURLStatus(job)
+		crawl_manager.BroadcastError(job, errorMsg)
+		slog.Warn("Janitor reaped stale job", "job_id", jobID, "url", job.URL, "status", job.Status)
+	}
+}
+
+// resetURLStatus marks the owning URL as failed if the reaped job is still its current job
+func (j *Janitor) resetURLStatus(job models.CrawlJob) {
+	urlRecord, err := j.urlRepo.GetByID(job.URLID)
+	if err != nil {
+		return
+	}
+	if urlRecord.JobId != fmt.Sprint(job.ID) {
+		return
+	}
+	if urlRecord.Status == "queued" || urlRecord.Status == "running" {
+		if err := j.urlRepo.UpdateStatus(urlRecord.ID, "error"); err != nil {
+			slog.Error("Janitor failed to reset URL status", "url_id", urlRecord.ID, "error", err)
+		}
+	}
+}
+
+// cleanupOldJobs archives or deletes finished jobs past the retention period
+func (j *Janitor) cleanupOldJobs() {
+	if j.config.RetentionDays == 0 {
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -j.config.RetentionDays)
+
+	var removed int64
+	var err error
+	if j.config.Mode == ModeDelete {
+		removed, err = j.jobRepo.PurgeOldJobs(cutoff)
+	} else {
+		removed, err = j.jobRepo.DeleteOldJobs(cutoff)
+	}
+
+	if err != nil {
+		slog.Error("Janitor failed to clean up old jobs", "error", err)
+		return
+	}
+	if removed > 0 {
+		slog.Info("Janitor cleaned up old jobs", "count", removed, "retention_days", j.config.RetentionDays, "mode", j.config.Mode)
+	}
+}
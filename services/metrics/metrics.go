@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+
+	"sykell-challenge/backend/services/taskq"
+	"sykell-challenge/backend/utils/egress"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "crawler"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	crawlEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "crawl_events_total",
+		Help:      "Crawl lifecycle events by type.",
+	}, []string{"type"})
+
+	crawlJobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "crawl_jobs_total",
+		Help:      "Finished crawl jobs by outcome.",
+	}, []string{"outcome"})
+
+	crawlDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "crawl_duration_seconds",
+		Help:      "Duration of finished crawl jobs from start to completion.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	}, []string{"outcome"})
+
+	linkChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "link_checks_total",
+		Help:      "Link checks by result class.",
+	}, []string{"result"})
+
+	linkCheckDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "link_check_duration_seconds",
+		Help:      "Latency of individual link checks.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
+	socketConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "socket_connections",
+		Help:      "Connected Socket.IO clients.",
+	})
+)
+
+func init() {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_depth",
+		Help:      "Crawl jobs waiting in this server's queue.",
+	}, func() float64 {
+		return float64(taskq.QueueLength())
+	})
+
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_running",
+		Help:      "Crawl jobs running on this server.",
+	}, func() float64 {
+		return float64(taskq.Stats().Running)
+	})
+
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_workers",
+		Help:      "Crawl jobs this server may run at once.",
+	}, func() float64 {
+		return float64(taskq.WorkerLimit())
+	})
+}
+
+// RegisterDB exports the connection pool statistics of db
+func RegisterDB(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "mysql"))
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// GinMiddleware records the count and latency of every request by route template
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			// Keep unknown paths from creating a series each
+			route = "unmatched"
+		}
+
+		httpRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// SocketConnected and SocketDisconnected track connected Socket.IO clients
+func SocketConnected() {
+	socketConnections.Inc()
+}
+
+func SocketDisconnected() {
+	socketConnections.Dec()
+}
+
+// ObserveLinkCheck records the latency and result class of a link check
+func ObserveLinkCheck(duration time.Duration, statusCode int, err error) {
+	linkCheckDuration.Observe(duration.Seconds())
+	linkChecks.WithLabelValues(LinkResultClass(statusCode, err)).Inc()
+}
+
+// LinkResultClass groups link check results into a small set of labels
+func LinkResultClass(statusCode int, err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+
+	switch {
+	case err == nil && statusCode >= 500:
+		return "http_5xx"
+	case err == nil && statusCode >= 400:
+		return "http_4xx"
+	case err == nil && statusCode > 0:
+		return "ok"
+	case egress.IsBlocked(err):
+		return "blocked"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &dnsErr):
+		return "dns"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connection_refused"
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return "connection_reset"
+	default:
+		return "other"
+	}
+}
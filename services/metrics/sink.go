@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"time"
+
+	"sykell-challenge/backend/services/events"
+)
+
+// timeLayout is the format of the timestamps in event payloads
+const timeLayout = "2006-01-02 15:04:05"
+
+// EventSink counts crawl events and records the outcome and duration of finished jobs
+type EventSink struct{}
+
+func (EventSink) Handle(event events.Event) {
+	crawlEvents.WithLabelValues(event.Type).Inc()
+
+	switch event.Type {
+	case events.CrawlCompleted, events.CrawlError, events.CrawlCancelled:
+	default:
+		return
+	}
+
+	outcome := event.Payload.Status
+	crawlJobs.WithLabelValues(outcome).Inc()
+
+	started, err := time.ParseInLocation(timeLayout, event.Payload.StartedAt, time.Local)
+	if err != nil {
+		return
+	}
+	completed, err := time.ParseInLocation(timeLayout, event.Payload.CompletedAt, time.Local)
+	if err != nil {
+		return
+	}
+	crawlDuration.WithLabelValues(outcome).Observe(completed.Sub(started).Seconds())
+}
@@ -0,0 +1,132 @@
+package socket
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"sykell-challenge/backend/db"
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/repositories"
+	"sykell-challenge/backend/services/events"
+
+	"github.com/zishang520/socket.io/v2/socket"
+)
+
+// Replay protocol: a client passes the last seq it saw as "lastSeq" in the
+// connection auth, or emits "replay" with {"lastSeq": n} after reconnecting.
+// It then receives the events it missed, or a "crawl_snapshot" of the active
+// jobs if they are no longer in the event history. Events may be delivered
+// twice around a reconnect; clients should ignore seqs they have seen.
+const (
+	replayRequestEvent = "replay"
+	snapshotEvent      = "crawl_snapshot"
+)
+
+// SnapshotMessage is the payload of crawl_snapshot events
+type SnapshotMessage struct {
+	Seq  uint64        `json:"seq"` // Continue from here; later events have a higher seq
+	Jobs []SnapshotJob `json:"jobs"`
+}
+
+// SnapshotJob is the state of an active job, with the same fields as the
+// crawl events. Socket clients are not authenticated, so job options and
+// checkpoints are never sent.
+type SnapshotJob struct {
+	JobID    string `json:"jobId"`
+	URL      string `json:"url"`
+	URLID    string `json:"urlId"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+}
+
+func newSnapshotJob(job models.CrawlJob) SnapshotJob {
+	return SnapshotJob{
+		JobID:    fmt.Sprint(job.ID),
+		URL:      job.URL,
+		URLID:    fmt.Sprint(job.URLID),
+		Status:   job.Status,
+		Progress: job.Progress,
+	}
+}
+
+// handleReplay wires the replay protocol for a newly connected client
+func handleReplay(client *socket.Socket) {
+	if lastSeq, ok := parseLastSeq(client.Handshake().Auth); ok {
+		replay(client, lastSeq)
+	}
+
+	client.On(replayRequestEvent, func(args ...any) {
+		if len(args) == 0 {
+			sendSnapshot(client)
+			return
+		}
+		lastSeq, ok := parseLastSeq(args[0])
+		if !ok {
+			sendSnapshot(client)
+			return
+		}
+		replay(client, lastSeq)
+	})
+}
+
+// replay sends the events after lastSeq, falling back to a snapshot when some are gone
+func replay(client *socket.Socket, lastSeq uint64) {
+	history := events.GetHistory()
+	if history == nil {
+		sendSnapshot(client)
+		return
+	}
+
+	records, complete := history.Since(lastSeq)
+	if !complete {
+		sendSnapshot(client)
+		return
+	}
+
+	for _, record := range records {
+		client.Emit(record.Type, record.Payload)
+	}
+	slog.Debug("Replayed events to socket client", "count", len(records), "client_id", client.Id())
+}
+
+// sendSnapshot sends the queued and running jobs to a client that cannot replay
+func sendSnapshot(client *socket.Socket) {
+	var seq uint64
+	if history := events.GetHistory(); history != nil {
+		// Taken before the query, so nothing published in between is skipped
+		seq = history.LastSeq()
+	}
+
+	jobs, err := repositories.NewCrawlJobRepository(db.GetDB()).GetActiveJobs()
+	if err != nil {
+		slog.Error("Failed to load active jobs for socket snapshot", "error", err)
+		return
+	}
+
+	snapshot := SnapshotMessage{Seq: seq, Jobs: make([]SnapshotJob, len(jobs))}
+	for i, job := range jobs {
+		snapshot.Jobs[i] = newSnapshotJob(job)
+	}
+	client.Emit(snapshotEvent, snapshot)
+}
+
+// parseLastSeq reads lastSeq from a {"lastSeq": n} object, as sent by socket.io clients
+func parseLastSeq(data any) (uint64, bool) {
+	fields, ok := data.(map[string]any)
+	if !ok {
+		return 0, false
+	}
+
+	switch value := fields["lastSeq"].(type) {
+	case float64:
+		if value < 0 {
+			return 0, false
+		}
+		return uint64(value), true
+	case string:
+		seq, err := strconv.ParseUint(value, 10, 64)
+		return seq, err == nil
+	}
+	return 0, false
+}
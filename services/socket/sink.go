@@ -0,0 +1,9 @@
+package socket
+
+import "sykell-challenge/backend/services/events"
+
+// ForwardRecord sends a numbered crawl event to all connected Socket.IO
+// clients; the payload's seq lets clients resume after a reconnect
+func ForwardRecord(record events.Record) {
+	BroadcastCrawlUpdate(record.Type, record.Payload)
+}
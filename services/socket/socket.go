@@ -1,9 +1,11 @@
 package socket
 
 import (
-	"fmt"
+	"log/slog"
 	"sync"
 
+	"sykell-challenge/backend/services/metrics"
+
 	"github.com/zishang520/engine.io/v2/types"
 	"github.com/zishang520/socket.io/v2/socket"
 )
@@ -13,10 +15,16 @@ var (
 	serverMutex  sync.RWMutex
 )
 
-func InitSocketServer() *socket.Server {
+// InitSocketServer creates the Socket.IO server; browsers may connect from allowedOrigins
+func InitSocketServer(allowedOrigins []string) *socket.Server {
+	origins := make([]any, len(allowedOrigins))
+	for i, origin := range allowedOrigins {
+		origins[i] = origin
+	}
+
 	opts := socket.DefaultServerOptions()
 	opts.SetCors(&types.Cors{
-		Origin:      "*",
+		Origin:      origins,
 		Credentials: true,
 	})
 	opts.SetTransports(types.NewSet("polling", "websocket"))
@@ -25,10 +33,17 @@ func InitSocketServer() *socket.Server {
 
 	server.On("connection", func(clients ...interface{}) {
 		client := clients[0].(*socket.Socket)
-		fmt.Println("Client connected:", client.Id())
+		slog.Debug("Socket client connected", "client_id", client.Id())
+		metrics.SocketConnected()
+		client.On("disconnect", func(...any) {
+			metrics.SocketDisconnected()
+		})
 
 		// Join client to a general room for broadcasts
 		client.Join("crawl_updates")
+
+		// Send what the client missed while it was disconnected
+		handleReplay(client)
 	})
 
 	// Store the server globally for broadcasting
@@ -47,9 +62,8 @@ func BroadcastCrawlUpdate(eventType string, data interface{}) {
 
 	if server != nil {
 		server.To("crawl_updates").Emit(eventType, data)
-		fmt.Printf("Broadcasted %s event to all clients\n", eventType)
 	} else {
-		fmt.Println("Socket server not initialized, cannot broadcast")
+		slog.Warn("Socket server not initialized, cannot broadcast", "event", eventType)
 	}
 }
 
@@ -0,0 +1,306 @@
+package taskq
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/antonmashko/taskq"
+)
+
+// ErrQueueFull is returned by Enqueue when the queue has reached its capacity
+var ErrQueueFull = errors.New("crawl queue is full")
+
+// JobInfo identifies a queued task for scheduling purposes
+type JobInfo struct {
+	JobID    string
+	UserID   uint
+	Priority int // Higher runs first within a user's queue
+}
+
+// SchedulableTask is a task that can be scheduled fairly between users
+type SchedulableTask interface {
+	taskq.Task
+	JobInfo() JobInfo
+}
+
+// QueuedJob describes a waiting task and its estimated position in the queue
+type QueuedJob struct {
+	JobID    string `json:"jobId"`
+	UserID   uint   `json:"userId"`
+	Priority int    `json:"priority"`
+	Position int    `json:"position"` // 1-based
+}
+
+type queueEntry struct {
+	task       taskq.Task
+	info       JobInfo
+	seq        int64
+	enqueuedAt time.Time
+}
+
+// FairQueue is a taskq.Queue that round-robins between users, orders each
+// user's tasks by priority and caps how many tasks a user may run at once.
+// It also limits the number of tasks running overall, which lets the worker
+// count change at runtime below the size of the taskq worker pool.
+type FairQueue struct {
+	mu             sync.Mutex
+	users          []uint                 // Users with waiting tasks, in round-robin order
+	pending        map[uint][]*queueEntry // Waiting tasks per user, highest priority first
+	running        map[uint]int           // Running tasks per user
+	next           int                    // Round-robin cursor into users
+	seq            int64
+	maxJobsPerUser int
+	workerLimit    int                 // Tasks allowed to run at once
+	capacity       int                 // Maximum waiting tasks; 0 means unlimited
+	workers        map[int]*runningJob // Running tasks by 1-based worker slot
+	stats          queueStats
+}
+
+// NewFairQueue creates a fair queue; maxJobsPerUser <= 0 disables the per-user
+// cap and capacity <= 0 allows any number of waiting tasks
+func NewFairQueue(maxJobsPerUser, workerLimit, capacity int) *FairQueue {
+	return &FairQueue{
+		pending:        make(map[uint][]*queueEntry),
+		running:        make(map[uint]int),
+		maxJobsPerUser: maxJobsPerUser,
+		workerLimit:    workerLimit,
+		capacity:       capacity,
+		workers:        make(map[int]*runningJob),
+	}
+}
+
+// Enqueue adds a task to its user's queue
+func (q *FairQueue) Enqueue(_ context.Context, task taskq.Task) (int64, error) {
+	if _, ok := task.(wakeup); ok {
+		// Only makes taskq start an idle worker, see Resize
+		return 0, nil
+	}
+
+	var info JobInfo
+	if schedulable, ok := task.(SchedulableTask); ok {
+		info = schedulable.JobInfo()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.capacity > 0 && q.countPending() >= q.capacity {
+		return -1, ErrQueueFull
+	}
+
+	q.seq++
+	entry := &queueEntry{task: task, info: info, seq: q.seq, enqueuedAt: time.Now()}
+
+	if _, exists := q.pending[info.UserID]; !exists {
+		q.users = append(q.users, info.UserID)
+	}
+	q.pending[info.UserID] = append(q.pending[info.UserID], entry)
+	sortEntries(q.pending[info.UserID])
+
+	return entry.seq, nil
+}
+
+// Dequeue returns the next task of the next user below their concurrency cap
+func (q *FairQueue) Dequeue(_ context.Context) (taskq.Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.workers) >= q.workerLimit {
+		return nil, taskq.EmptyQueue
+	}
+
+	for i := 0; i < len(q.users); i++ {
+		index := (q.next + i) % len(q.users)
+		userID := q.users[index]
+		if q.maxJobsPerUser > 0 && q.running[userID] >= q.maxJobsPerUser {
+			continue
+		}
+
+		entries := q.pending[userID]
+		entry := entries[0]
+		q.pending[userID] = entries[1:]
+		q.running[userID]++
+
+		if len(q.pending[userID]) == 0 {
+			q.removeUser(index)
+			q.next = index
+		} else {
+			q.next = index + 1
+		}
+		if len(q.users) > 0 {
+			q.next %= len(q.users)
+		} else {
+			q.next = 0
+		}
+
+		slot := q.startJob(entry)
+		return &fairTask{Task: entry.task, release: func() { q.release(userID, slot) }}, nil
+	}
+
+	return nil, taskq.EmptyQueue
+}
+
+// SetPriority changes the priority of a waiting job
+func (q *FairQueue) SetPriority(jobID string, priority int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for userID, entries := range q.pending {
+		for _, entry := range entries {
+			if entry.info.JobID == jobID {
+				entry.info.Priority = priority
+				sortEntries(q.pending[userID])
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Remove drops a waiting job from the queue
+func (q *FairQueue) Remove(jobID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for index, userID := range q.users {
+		entries := q.pending[userID]
+		for i, entry := range entries {
+			if entry.info.JobID != jobID {
+				continue
+			}
+			q.pending[userID] = append(entries[:i:i], entries[i+1:]...)
+			if len(q.pending[userID]) == 0 {
+				q.removeUser(index)
+				if q.next > index {
+					q.next--
+				}
+				if q.next >= len(q.users) {
+					q.next = 0
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot lists the waiting jobs in the order they are expected to run,
+// assuming no user hits their concurrency cap in the meantime
+func (q *FairQueue) Snapshot() []QueuedJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cursors := make(map[uint]int, len(q.users))
+	var jobs []QueuedJob
+	for remaining := true; remaining; {
+		remaining = false
+		for i := 0; i < len(q.users); i++ {
+			userID := q.users[(q.next+i)%len(q.users)]
+			entries := q.pending[userID]
+			if cursors[userID] >= len(entries) {
+				continue
+			}
+			entry := entries[cursors[userID]]
+			cursors[userID]++
+			remaining = true
+			jobs = append(jobs, QueuedJob{
+				JobID:    entry.info.JobID,
+				UserID:   entry.info.UserID,
+				Priority: entry.info.Priority,
+				Position: len(jobs) + 1,
+			})
+		}
+	}
+	return jobs
+}
+
+// Len returns the number of waiting tasks
+func (q *FairQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.countPending()
+}
+
+// SetWorkerLimit changes how many tasks may run at once. Running tasks above
+// a lowered limit finish normally.
+func (q *FairQueue) SetWorkerLimit(limit int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.workerLimit = limit
+}
+
+// WorkerLimit returns how many tasks may run at once
+func (q *FairQueue) WorkerLimit() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.workerLimit
+}
+
+// SetCapacity changes how many tasks may wait; 0 means unlimited
+func (q *FairQueue) SetCapacity(capacity int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.capacity = capacity
+}
+
+// countPending returns the number of waiting tasks; callers must hold mu
+func (q *FairQueue) countPending() int {
+	count := 0
+	for _, entries := range q.pending {
+		count += len(entries)
+	}
+	return count
+}
+
+func (q *FairQueue) release(userID uint, slot int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.running[userID]--
+	if q.running[userID] <= 0 {
+		delete(q.running, userID)
+	}
+	q.finishJob(slot)
+}
+
+// removeUser drops a user without waiting tasks from the rotation; callers must hold mu
+func (q *FairQueue) removeUser(index int) {
+	delete(q.pending, q.users[index])
+	q.users = append(q.users[:index:index], q.users[index+1:]...)
+}
+
+func sortEntries(entries []*queueEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].info.Priority != entries[j].info.Priority {
+			return entries[i].info.Priority > entries[j].info.Priority
+		}
+		return entries[i].seq < entries[j].seq
+	})
+}
+
+// fairTask frees its user's concurrency slot once the wrapped task returns,
+// before the worker dequeues its next task
+type fairTask struct {
+	taskq.Task
+	release func()
+}
+
+func (t *fairTask) Do(ctx context.Context) error {
+	defer t.release()
+	return t.Task.Do(ctx)
+}
+
+func (t *fairTask) Done(ctx context.Context) {
+	if done, ok := t.Task.(taskq.TaskDone); ok {
+		done.Done(ctx)
+	}
+}
+
+func (t *fairTask) OnError(ctx context.Context, err error) {
+	if onError, ok := t.Task.(taskq.TaskOnError); ok {
+		onError.OnError(ctx, err)
+	}
+}
@@ -0,0 +1,193 @@
+package taskq
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/antonmashko/taskq"
+)
+
+type testTask struct {
+	info JobInfo
+}
+
+func (t *testTask) Do(context.Context) error {
+	return nil
+}
+
+func (t *testTask) JobInfo() JobInfo {
+	return t.info
+}
+
+func enqueueJobs(t *testing.T, q *FairQueue, jobs ...JobInfo) {
+	t.Helper()
+	for _, info := range jobs {
+		if _, err := q.Enqueue(context.Background(), &testTask{info: info}); err != nil {
+			t.Fatalf("Enqueue(%s) error = %v", info.JobID, err)
+		}
+	}
+}
+
+// dequeueAll takes tasks until the queue reports empty, without finishing them
+func dequeueAll(t *testing.T, q *FairQueue) (ids []string, tasks []taskq.Task) {
+	t.Helper()
+	for {
+		task, err := q.Dequeue(context.Background())
+		if errors.Is(err, taskq.EmptyQueue) {
+			return ids, tasks
+		}
+		if err != nil {
+			t.Fatalf("Dequeue() error = %v", err)
+		}
+		ids = append(ids, task.(*fairTask).Task.(*testTask).info.JobID)
+		tasks = append(tasks, task)
+	}
+}
+
+func snapshotIDs(q *FairQueue) []string {
+	var ids []string
+	for i, job := range q.Snapshot() {
+		if job.Position != i+1 {
+			return nil
+		}
+		ids = append(ids, job.JobID)
+	}
+	return ids
+}
+
+func TestFairQueueScheduling(t *testing.T) {
+	tests := []struct {
+		name string
+		jobs []JobInfo
+		want []string
+	}{
+		{
+			name: "single user runs in submission order",
+			jobs: []JobInfo{{JobID: "a", UserID: 1}, {JobID: "b", UserID: 1}, {JobID: "c", UserID: 1}},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "users take turns",
+			jobs: []JobInfo{
+				{JobID: "a1", UserID: 1}, {JobID: "a2", UserID: 1}, {JobID: "a3", UserID: 1},
+				{JobID: "b1", UserID: 2},
+				{JobID: "c1", UserID: 3}, {JobID: "c2", UserID: 3},
+			},
+			want: []string{"a1", "b1", "c1", "a2", "c2", "a3"},
+		},
+		{
+			name: "priority orders a user's own jobs only",
+			jobs: []JobInfo{
+				{JobID: "a-low", UserID: 1, Priority: 0},
+				{JobID: "a-normal", UserID: 1, Priority: 1},
+				{JobID: "a-high", UserID: 1, Priority: 2},
+				{JobID: "b-low", UserID: 2, Priority: 0},
+			},
+			want: []string{"a-high", "b-low", "a-normal", "a-low"},
+		},
+		{
+			name: "equal priorities keep submission order",
+			jobs: []JobInfo{
+				{JobID: "first", UserID: 1, Priority: 2},
+				{JobID: "low", UserID: 1, Priority: 0},
+				{JobID: "second", UserID: 1, Priority: 2},
+			},
+			want: []string{"first", "second", "low"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewFairQueue(0, 100, 0)
+			enqueueJobs(t, q, tt.jobs...)
+
+			if got := snapshotIDs(q); !slices.Equal(got, tt.want) {
+				t.Errorf("Snapshot() = %v, want %v", got, tt.want)
+			}
+			if got, _ := dequeueAll(t, q); !slices.Equal(got, tt.want) {
+				t.Errorf("dequeue order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFairQueuePerUserCap(t *testing.T) {
+	q := NewFairQueue(1, 100, 0)
+	enqueueJobs(t, q,
+		JobInfo{JobID: "a1", UserID: 1}, JobInfo{JobID: "a2", UserID: 1},
+		JobInfo{JobID: "b1", UserID: 2},
+	)
+
+	ids, tasks := dequeueAll(t, q)
+	if want := []string{"a1", "b1"}; !slices.Equal(ids, want) {
+		t.Fatalf("dequeued %v while user 1 is at the cap, want %v", ids, want)
+	}
+
+	// Finishing a1 frees user 1's slot
+	if err := tasks[0].Do(context.Background()); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if ids, _ := dequeueAll(t, q); !slices.Equal(ids, []string{"a2"}) {
+		t.Fatalf("dequeued %v after a1 finished, want [a2]", ids)
+	}
+}
+
+func TestFairQueueWorkerLimit(t *testing.T) {
+	q := NewFairQueue(0, 1, 0)
+	enqueueJobs(t, q, JobInfo{JobID: "a", UserID: 1}, JobInfo{JobID: "b", UserID: 2})
+
+	ids, tasks := dequeueAll(t, q)
+	if !slices.Equal(ids, []string{"a"}) {
+		t.Fatalf("dequeued %v with one worker, want [a]", ids)
+	}
+
+	q.SetWorkerLimit(2)
+	if ids, _ := dequeueAll(t, q); !slices.Equal(ids, []string{"b"}) {
+		t.Fatalf("dequeued %v after raising the limit, want [b]", ids)
+	}
+	if err := tasks[0].Do(context.Background()); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if stats := q.Stats(); stats.Running != 1 || stats.CompletedTotal != 1 {
+		t.Fatalf("Stats() running = %d, completed = %d, want 1 and 1", stats.Running, stats.CompletedTotal)
+	}
+}
+
+func TestFairQueueCapacity(t *testing.T) {
+	q := NewFairQueue(0, 100, 2)
+	enqueueJobs(t, q, JobInfo{JobID: "a", UserID: 1}, JobInfo{JobID: "b", UserID: 2})
+
+	if _, err := q.Enqueue(context.Background(), &testTask{info: JobInfo{JobID: "c", UserID: 3}}); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Enqueue() beyond capacity error = %v, want ErrQueueFull", err)
+	}
+
+	dequeueAll(t, q)
+	enqueueJobs(t, q, JobInfo{JobID: "c", UserID: 3})
+}
+
+func TestFairQueueSetPriorityAndRemove(t *testing.T) {
+	q := NewFairQueue(0, 100, 0)
+	enqueueJobs(t, q,
+		JobInfo{JobID: "a1", UserID: 1}, JobInfo{JobID: "a2", UserID: 1}, JobInfo{JobID: "a3", UserID: 1},
+		JobInfo{JobID: "b1", UserID: 2},
+	)
+
+	if !q.SetPriority("a3", 2) {
+		t.Fatal("SetPriority(a3) = false, want true")
+	}
+	if !q.Remove("b1") {
+		t.Fatal("Remove(b1) = false, want true")
+	}
+	if q.Remove("b1") || q.SetPriority("missing", 2) {
+		t.Fatal("changing a job that is not waiting succeeded")
+	}
+
+	if got, want := snapshotIDs(q), []string{"a3", "a1", "a2"}; !slices.Equal(got, want) {
+		t.Fatalf("Snapshot() = %v, want %v", got, want)
+	}
+	if q.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", q.Len())
+	}
+}
@@ -0,0 +1,137 @@
+package taskq
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	waitSamples      = 100             // Dequeued jobs the average wait is computed over
+	throughputWindow = 5 * time.Minute // Period the throughput is computed over
+)
+
+// WorkerStatus describes what a worker slot is doing; JobID is empty while it is idle
+type WorkerStatus struct {
+	Worker    int        `json:"worker"` // 1-based
+	JobID     string     `json:"jobId,omitempty"`
+	UserID    uint       `json:"userId,omitempty"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+}
+
+// QueueStats is a point-in-time view of the queue and its workers
+type QueueStats struct {
+	Workers             int            `json:"workers"`    // Tasks allowed to run at once
+	MaxWorkers          int            `json:"maxWorkers"` // Upper bound for resizing
+	Capacity            int            `json:"capacity"`   // Maximum waiting tasks; 0 means unlimited
+	Queued              int            `json:"queued"`
+	Running             int            `json:"running"`
+	WorkerJobs          []WorkerStatus `json:"workerJobs"`
+	CompletedTotal      int64          `json:"completedTotal"`      // Since the server started
+	ThroughputPerMinute float64        `json:"throughputPerMinute"` // Over the last five minutes
+	AvgWaitSeconds      float64        `json:"avgWaitSeconds"`      // Over the last 100 started jobs
+}
+
+type runningJob struct {
+	info      JobInfo
+	startedAt time.Time
+}
+
+// queueStats collects wait times and completions
+type queueStats struct {
+	waits          []time.Duration // Waits of the last waitSamples started jobs, oldest first
+	completions    []time.Time     // Completions within throughputWindow, oldest first
+	completedTotal int64
+}
+
+// wakeup is enqueued to make taskq start idle workers after the worker limit
+// was raised; FairQueue drops it
+type wakeup struct{}
+
+func (wakeup) Do(context.Context) error {
+	return nil
+}
+
+// startJob records a dequeued entry in the lowest free worker slot; callers must hold mu
+func (q *FairQueue) startJob(entry *queueEntry) int {
+	slot := 1
+	for q.workers[slot] != nil {
+		slot++
+	}
+
+	now := time.Now()
+	q.workers[slot] = &runningJob{info: entry.info, startedAt: now}
+
+	q.stats.waits = append(q.stats.waits, now.Sub(entry.enqueuedAt))
+	if len(q.stats.waits) > waitSamples {
+		q.stats.waits = q.stats.waits[len(q.stats.waits)-waitSamples:]
+	}
+	return slot
+}
+
+// finishJob frees a worker slot; callers must hold mu
+func (q *FairQueue) finishJob(slot int) {
+	delete(q.workers, slot)
+
+	now := time.Now()
+	q.stats.completedTotal++
+	q.stats.completions = append(q.stats.completions, now)
+	q.pruneCompletions(now)
+}
+
+// pruneCompletions drops completions outside throughputWindow; callers must hold mu
+func (q *FairQueue) pruneCompletions(now time.Time) {
+	cutoff := now.Add(-throughputWindow)
+	i := 0
+	for i < len(q.stats.completions) && q.stats.completions[i].Before(cutoff) {
+		i++
+	}
+	q.stats.completions = q.stats.completions[i:]
+}
+
+// Stats reports the queue length, the job of every worker slot, the
+// throughput and the average time jobs waited before starting
+func (q *FairQueue) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pruneCompletions(time.Now())
+
+	slots := q.workerLimit
+	for slot := range q.workers {
+		if slot > slots {
+			slots = slot
+		}
+	}
+
+	workerJobs := make([]WorkerStatus, 0, slots)
+	for slot := 1; slot <= slots; slot++ {
+		status := WorkerStatus{Worker: slot}
+		if job := q.workers[slot]; job != nil {
+			startedAt := job.startedAt
+			status.JobID = job.info.JobID
+			status.UserID = job.info.UserID
+			status.StartedAt = &startedAt
+		}
+		workerJobs = append(workerJobs, status)
+	}
+
+	var avgWait float64
+	if len(q.stats.waits) > 0 {
+		var total time.Duration
+		for _, wait := range q.stats.waits {
+			total += wait
+		}
+		avgWait = (total / time.Duration(len(q.stats.waits))).Seconds()
+	}
+
+	return QueueStats{
+		Workers:             q.workerLimit,
+		Capacity:            q.capacity,
+		Queued:              q.countPending(),
+		Running:             len(q.workers),
+		WorkerJobs:          workerJobs,
+		CompletedTotal:      q.stats.completedTotal,
+		ThroughputPerMinute: float64(len(q.stats.completions)) / throughputWindow.Minutes(),
+		AvgWaitSeconds:      avgWait,
+	}
+}
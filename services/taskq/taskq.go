@@ -2,8 +2,9 @@ package taskq
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -12,23 +13,96 @@ import (
 
 var (
 	TaskQueue *taskq.TaskQ
+	// Fair-share queue backing TaskQueue
+	jobQueue *FairQueue
+	// Size of the taskq worker pool; the worker count can be raised up to it
+	maxWorkers int
 	// Track running jobs for cancellation
-	runningJobs = make(map[string]context.CancelFunc)
+	runningJobs = make(map[string]context.CancelCauseFunc)
 	jobsMutex   sync.RWMutex
 )
 
+// ErrInvalidWorkerCount is returned by Resize for counts outside 1..MaxWorkers
+var ErrInvalidWorkerCount = errors.New("invalid worker count")
+
+// Config holds scheduler configuration
+type Config struct {
+	MaxJobsPerUser int `yaml:"maxJobsPerUser"` // Concurrent crawls per user; 0 disables the cap
+	Workers        int `yaml:"workers"`        // Concurrent crawls on this server
+	MaxWorkers     int `yaml:"maxWorkers"`     // Upper bound for resizing the worker count at runtime
+	QueueCapacity  int `yaml:"queueCapacity"`  // Maximum waiting crawls; 0 means unlimited
+}
+
+// DefaultConfig returns the default scheduler configuration
+func DefaultConfig() *Config {
+	return &Config{
+		MaxJobsPerUser: 2,
+		Workers:        5,
+		MaxWorkers:     50,
+		QueueCapacity:  1000,
+	}
+}
+
 // InitTaskQueue initializes the task queue
-func InitTaskQueue() {
-	// Create task queue with worker limit of 5
-	TaskQueue = taskq.New(5)
+func InitTaskQueue(config *Config) {
+	jobQueue = NewFairQueue(config.MaxJobsPerUser, config.Workers, config.QueueCapacity)
+
+	// The pool holds the most workers we may resize to; the queue only lets
+	// the configured number of them run tasks
+	maxWorkers = config.MaxWorkers
+	TaskQueue = taskq.NewWithQueue(config.MaxWorkers, jobQueue)
 
 	// Start the task queue
 	if err := TaskQueue.Start(); err != nil {
-		log.Printf("Failed to start task queue: %v", err)
+		slog.Error("Failed to start task queue", "error", err)
 		return
 	}
 
-	log.Println("Task queue initialized with 5 workers")
+	slog.Info("Task queue initialized", "workers", config.Workers, "max_workers", config.MaxWorkers,
+		"capacity", config.QueueCapacity, "max_jobs_per_user", config.MaxJobsPerUser)
+}
+
+// Resize changes the number of concurrent crawls. Added workers pick up
+// waiting jobs right away; when shrinking, running crawls finish first.
+func Resize(workers int) error {
+	if jobQueue == nil {
+		return errors.New("task queue is not running on this server")
+	}
+	if workers < 1 || workers > maxWorkers {
+		return fmt.Errorf("%w: must be between 1 and %d", ErrInvalidWorkerCount, maxWorkers)
+	}
+
+	previous := jobQueue.WorkerLimit()
+	jobQueue.SetWorkerLimit(workers)
+
+	// taskq only starts idle workers when something is enqueued
+	for i := previous; i < workers; i++ {
+		if _, err := TaskQueue.Enqueue(context.Background(), wakeup{}); err != nil {
+			return err
+		}
+	}
+
+	slog.Info("Task queue resized", "from", previous, "to", workers)
+	return nil
+}
+
+// Stats reports the state of this server's queue and workers
+func Stats() QueueStats {
+	if jobQueue == nil {
+		return QueueStats{WorkerJobs: []WorkerStatus{}}
+	}
+
+	stats := jobQueue.Stats()
+	stats.MaxWorkers = maxWorkers
+	return stats
+}
+
+// WorkerLimit returns the number of concurrent crawls
+func WorkerLimit() int {
+	if jobQueue == nil {
+		return 0
+	}
+	return jobQueue.WorkerLimit()
 }
 
 // ShutdownTaskQueue gracefully shuts down the task queue
@@ -38,9 +112,9 @@ func ShutdownTaskQueue() {
 		defer cancel()
 
 		if err := TaskQueue.Shutdown(ctx); err != nil {
-			log.Printf("Error shutting down task queue: %v", err)
+			slog.Error("Error shutting down task queue", "error", err)
 		} else {
-			log.Println("Task queue shut down gracefully")
+			slog.Info("Task queue shut down gracefully")
 		}
 	}
 }
@@ -53,11 +127,78 @@ func EnqueueTask(ctx context.Context, task taskq.Task) (int64, error) {
 	return 0, nil
 }
 
-// RegisterJob registers a job with its cancel function
-func RegisterJob(jobID string, cancel context.CancelFunc) {
+// Started reports whether this process runs crawl workers
+func Started() bool {
+	return TaskQueue != nil
+}
+
+// QueueLength returns the number of waiting tasks
+func QueueLength() int {
+	if jobQueue == nil {
+		return 0
+	}
+	return jobQueue.Len()
+}
+
+// SetJobPriority changes the priority of a queued job
+func SetJobPriority(jobID string, priority int) bool {
+	if jobQueue == nil {
+		return false
+	}
+	return jobQueue.SetPriority(jobID, priority)
+}
+
+// RemoveQueuedJob drops a job that has not started yet from the queue
+func RemoveQueuedJob(jobID string) bool {
+	if jobQueue == nil {
+		return false
+	}
+	return jobQueue.Remove(jobID)
+}
+
+// QueuedJobs lists waiting jobs in their expected execution order
+func QueuedJobs() []QueuedJob {
+	if jobQueue == nil {
+		return nil
+	}
+	return jobQueue.Snapshot()
+}
+
+// QueuePosition returns the 1-based position of a waiting job
+func QueuePosition(jobID string) (int, bool) {
+	for _, job := range QueuedJobs() {
+		if job.JobID == jobID {
+			return job.Position, true
+		}
+	}
+	return 0, false
+}
+
+// LocalJobIDs lists the jobs this process is running or holding in its queue
+func LocalJobIDs() []string {
+	jobsMutex.RLock()
+	ids := make([]string, 0, len(runningJobs))
+	for jobID := range runningJobs {
+		ids = append(ids, jobID)
+	}
+	jobsMutex.RUnlock()
+
+	for _, job := range QueuedJobs() {
+		ids = append(ids, job.JobID)
+	}
+	return ids
+}
+
+// RegisterJob registers a job with its cancel function; it reports false if
+// the job is already running on this node
+func RegisterJob(jobID string, cancel context.CancelCauseFunc) bool {
 	jobsMutex.Lock()
 	defer jobsMutex.Unlock()
+	if _, exists := runningJobs[jobID]; exists {
+		return false
+	}
 	runningJobs[jobID] = cancel
+	return true
 }
 
 // UnregisterJob removes a job from tracking
@@ -69,14 +210,18 @@ func UnregisterJob(jobID string) {
 
 // CancelJob cancels a running job by its ID
 func CancelJob(jobID string) bool {
-	fmt.Println("Running Jobs:", runningJobs)
-	fmt.Println("Attempting to cancel job:", jobID)
+	slog.Debug("Attempting to cancel job", "job_id", jobID)
+	return CancelJobWithCause(jobID, context.Canceled)
+}
+
+// CancelJobWithCause stops a running job, telling it why via context.Cause
+func CancelJobWithCause(jobID string, cause error) bool {
 	jobsMutex.RLock()
 	cancel, exists := runningJobs[jobID]
 	jobsMutex.RUnlock()
 
 	if exists {
-		cancel()
+		cancel(cause)
 		return true
 	}
 	return false
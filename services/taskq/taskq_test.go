@@ -0,0 +1,31 @@
+package taskq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegisterJobOncePerNode(t *testing.T) {
+	first, cancelFirst := context.WithCancelCause(context.Background())
+	defer cancelFirst(nil)
+	_, cancelSecond := context.WithCancelCause(context.Background())
+	defer cancelSecond(nil)
+
+	if !RegisterJob("42", cancelFirst) {
+		t.Fatalf("RegisterJob() = false for a new job")
+	}
+	defer UnregisterJob("42")
+
+	if RegisterJob("42", cancelSecond) {
+		t.Fatalf("RegisterJob() = true for a job that is already running")
+	}
+
+	// The first run must still be reachable for pause and cancel
+	if !CancelJob("42") {
+		t.Fatalf("CancelJob() = false, want the first run cancelled")
+	}
+	if !errors.Is(context.Cause(first), context.Canceled) {
+		t.Errorf("first run cause = %v, want context.Canceled", context.Cause(first))
+	}
+}
@@ -0,0 +1,26 @@
+package telemetry
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+var serviceName = "sykell-crawler"
+
+// untracedPaths are polled by infrastructure and would flood the traces
+var untracedPaths = map[string]bool{
+	"/metrics": true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// GinMiddleware starts a server span for every request. Metric scrapes,
+// probes and Socket.IO polling are not traced.
+func GinMiddleware() gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path] && !strings.HasPrefix(r.URL.Path, "/socket.io/")
+	}))
+}
@@ -0,0 +1,27 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const traceParentKey = "traceparent"
+
+// TraceParent returns the W3C traceparent of the span in ctx, or an empty
+// string outside of a trace. It is stored with queued jobs so the worker
+// that runs them continues the trace of the request that queued them.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier.Get(traceParentKey)
+}
+
+// ContextWithTraceParent returns ctx as part of the trace identified by traceParent
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier{traceParentKey: traceParent})
+}
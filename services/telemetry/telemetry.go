@@ -0,0 +1,117 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterNone records no traces
+	ExporterNone = "none"
+	// ExporterOTLP sends traces to an OpenTelemetry collector over OTLP/HTTP
+	ExporterOTLP = "otlp"
+)
+
+// instrumentationName names the tracer of the application's own spans
+const instrumentationName = "sykell-challenge/backend"
+
+// Config holds tracing configuration
+type Config struct {
+	Exporter    string  `yaml:"exporter"`    // ExporterNone or ExporterOTLP
+	ServiceName string  `yaml:"serviceName"` // Reported as service.name
+	SampleRatio float64 `yaml:"sampleRatio"` // Share of new traces that are recorded, 0 to 1
+}
+
+// DefaultConfig returns the default tracing configuration. The OTLP
+// endpoint, headers and timeout come from the standard
+// OTEL_EXPORTER_OTLP_* variables.
+func DefaultConfig() *Config {
+	return &Config{
+		Exporter:    ExporterNone,
+		ServiceName: "sykell-crawler",
+		SampleRatio: 1,
+	}
+}
+
+// Init installs the global tracer provider and the W3C trace context
+// propagator. Without an exporter the provider stays a no-op. The returned
+// function flushes and stops the exporter.
+func Init(config *Config, nodeID string) func(context.Context) error {
+	serviceName = config.ServiceName
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	noop := func(context.Context) error { return nil }
+	if config.Exporter == ExporterNone {
+		return noop
+	}
+
+	ctx := context.Background()
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		slog.Error("Failed to create trace exporter, tracing disabled", "error", err)
+		return noop
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(config.ServiceName),
+			semconv.ServiceInstanceID(nodeID),
+		),
+	)
+	if err != nil {
+		slog.Warn("Incomplete trace resource", "error", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(rootSampler{sdktrace.TraceIDRatioBased(config.SampleRatio)})),
+	)
+	otel.SetTracerProvider(provider)
+
+	slog.Info("Tracing enabled", "exporter", config.Exporter, "service", config.ServiceName, "sample_ratio", config.SampleRatio)
+	return provider.Shutdown
+}
+
+// Tracer returns the tracer for the application's own spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// rootSampler drops database queries that run outside of any trace, such as
+// the broker's polling, instead of starting a trace for each of them
+type rootSampler struct {
+	sdktrace.Sampler
+}
+
+func (s rootSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if strings.HasPrefix(p.Name, "gorm.") {
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop}
+	}
+	return s.Sampler.ShouldSample(p)
+}
+
+func (s rootSampler) Description() string {
+	return "SkipOrphanQueries{" + s.Sampler.Description() + "}"
+}
+
+// RecordError marks span as failed with err; a nil err leaves it unchanged
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
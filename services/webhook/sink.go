@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/services/events"
+)
+
+// EventSink turns crawl events into webhook deliveries for the job's owner
+type EventSink struct{}
+
+func (EventSink) Handle(event events.Event) {
+	switch event.Type {
+	case events.CrawlCompleted:
+		Publish(event.UserID, models.WebhookEventCrawlCompleted, event.Payload)
+
+		var brokenLinks models.Links
+		for _, link := range event.Payload.Links {
+			if link.Type == "inaccessible" {
+				brokenLinks = append(brokenLinks, link)
+			}
+		}
+		if len(brokenLinks) > 0 {
+			// Same shape as crawl_completed, limited to the broken links
+			payload := event.Payload
+			payload.Links = brokenLinks
+			payload.LinksCount = len(brokenLinks)
+			payload.Tags = nil
+			payload.TagsCount = 0
+			Publish(event.UserID, models.WebhookEventBrokenLinksFound, payload)
+		}
+
+	case events.CrawlError:
+		Publish(event.UserID, models.WebhookEventCrawlError, event.Payload)
+	}
+}
@@ -0,0 +1,348 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/repositories"
+	"sykell-challenge/backend/utils/egress"
+
+	"gorm.io/gorm"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>"
+)
+
+// Config holds webhook delivery configuration
+type Config struct {
+	MaxAttempts  int           `yaml:"maxAttempts"`  // Attempts before a delivery is marked failed
+	RetryDelay   time.Duration `yaml:"retryDelay"`   // Delay before the first retry, doubled on each further retry
+	Timeout      time.Duration `yaml:"timeout"`      // Per-request timeout
+	PollInterval time.Duration `yaml:"pollInterval"` // How often due retries are picked up
+}
+
+// DefaultConfig returns the default webhook configuration
+func DefaultConfig() *Config {
+	return &Config{
+		MaxAttempts:  5,
+		RetryDelay:   30 * time.Second,
+		Timeout:      10 * time.Second,
+		PollInterval: 5 * time.Second,
+	}
+}
+
+// Envelope is the JSON body of a delivery; Data has the same shape as the
+// matching Socket.IO event
+type Envelope struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Dispatcher stores webhook deliveries and sends them with retries
+type Dispatcher struct {
+	config   *Config
+	repo     *repositories.WebhookRepository
+	client   *http.Client
+	kick     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+var globalDispatcher *Dispatcher
+
+// InitDispatcher creates the global dispatcher used by Publish
+func InitDispatcher(config *Config, db *gorm.DB) *Dispatcher {
+	pool, err := egress.DefaultPool()
+	if err != nil {
+		slog.Warn("Invalid proxy configuration, sending webhooks directly", "error", err)
+		pool = nil
+	}
+
+	globalDispatcher = &Dispatcher{
+		config: config,
+		repo:   repositories.NewWebhookRepository(db),
+		client: &http.Client{
+			// Webhook targets are user-supplied, so they pass the same SSRF guard as crawls
+			Transport: egress.TransportFor(pool),
+			Timeout:   config.Timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		kick: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	return globalDispatcher
+}
+
+// GetDispatcher returns the global dispatcher, or nil before InitDispatcher
+func GetDispatcher() *Dispatcher {
+	return globalDispatcher
+}
+
+// Publish queues event for every webhook of userID subscribed to it
+func Publish(userID uint, event string, data interface{}) {
+	if globalDispatcher == nil || userID == 0 {
+		return
+	}
+	globalDispatcher.Publish(userID, event, data)
+}
+
+// Publish queues event for every webhook of userID subscribed to it
+func (d *Dispatcher) Publish(userID uint, event string, data interface{}) {
+	webhooks, err := d.repo.GetSubscribed(userID, event)
+	if err != nil {
+		slog.Error("Failed to load webhooks", "user_id", userID, "error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(Envelope{Event: event, Timestamp: time.Now().UTC(), Data: data})
+	if err != nil {
+		slog.Error("Failed to encode webhook payload", "event", event, "error", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if _, err := d.enqueue(webhook.ID, event, string(payload), nil, time.Now()); err != nil {
+			slog.Error("Failed to queue webhook delivery", "webhook_id", webhook.ID, "error", err)
+		}
+	}
+
+	select {
+	case d.kick <- struct{}{}:
+	default:
+	}
+}
+
+// SendTest delivers a test event to a webhook right away and returns the delivery
+func (d *Dispatcher) SendTest(webhook *models.Webhook) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(Envelope{
+		Event:     models.WebhookEventTest,
+		Timestamp: time.Now().UTC(),
+		Data:      map[string]interface{}{"webhookId": webhook.ID, "message": "Test delivery"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := d.enqueue(webhook.ID, models.WebhookEventTest, string(payload), nil, d.claimedUntil())
+	if err != nil {
+		return nil, err
+	}
+
+	d.attempt(webhook, delivery)
+	return delivery, nil
+}
+
+// Replay sends the payload of an earlier delivery again as a new delivery
+func (d *Dispatcher) Replay(webhook *models.Webhook, original *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	delivery, err := d.enqueue(webhook.ID, original.Event, original.Payload, &original.ID, d.claimedUntil())
+	if err != nil {
+		return nil, err
+	}
+
+	d.attempt(webhook, delivery)
+	return delivery, nil
+}
+
+// Start delivers due deliveries in the background until Stop is called
+func (d *Dispatcher) Start() {
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-d.kick:
+			case <-d.stop:
+				return
+			}
+			d.deliverDue()
+		}
+	}()
+
+	slog.Info("Webhook dispatcher started", "max_attempts", d.config.MaxAttempts, "timeout", d.config.Timeout.String())
+}
+
+// Stop stops the dispatcher and waits for a running delivery pass to finish
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+	})
+	<-d.done
+}
+
+// enqueue stores a pending delivery that the poller picks up from due on.
+// Deliveries sent right away are created already claimed, with due set by claimedUntil.
+func (d *Dispatcher) enqueue(webhookID uint, event, payload string, replayOf *uint, due time.Time) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{
+		WebhookID:     webhookID,
+		Event:         event,
+		Payload:       payload,
+		Status:        "pending",
+		NextAttemptAt: &due,
+		ReplayOf:      replayOf,
+	}
+	if err := d.repo.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// deliverDue sends every pending delivery whose next attempt is due
+func (d *Dispatcher) deliverDue() {
+	now := time.Now()
+	deliveries, err := d.repo.GetDueDeliveries(now, 100)
+	if err != nil {
+		slog.Error("Failed to load due webhook deliveries", "error", err)
+		return
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		// Several servers may poll the same table; only one sends each delivery
+		claimed, err := d.repo.ClaimDelivery(delivery.ID, now, d.claimedUntil())
+		if err != nil || !claimed {
+			continue
+		}
+
+		webhook, err := d.repo.GetByID(delivery.WebhookID)
+		if err != nil || !webhook.Active {
+			// Webhook deleted or disabled in the meantime
+			delivery.Status = "failed"
+			delivery.LastError = "webhook no longer active"
+			delivery.NextAttemptAt = nil
+			d.repo.UpdateDelivery(delivery)
+			continue
+		}
+
+		d.attempt(webhook, delivery)
+	}
+}
+
+// claimedUntil is when a claimed delivery becomes due again if its sender
+// never records the outcome
+func (d *Dispatcher) claimedUntil() time.Time {
+	return time.Now().Add(2 * d.config.Timeout)
+}
+
+// attempt sends a delivery once and schedules a retry or marks it finished.
+// The caller must hold the claim on the delivery.
+func (d *Dispatcher) attempt(webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+	statusCode, err := d.send(webhook, delivery)
+	delivery.ResponseCode = statusCode
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = "succeeded"
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+
+	case delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = "failed"
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = nil
+		slog.Warn("Webhook delivery failed", "webhook_id", webhook.ID, "delivery_id", delivery.ID, "attempts", delivery.Attempts, "error", err)
+
+	default:
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = &next
+	}
+
+	if err := d.repo.UpdateDelivery(delivery); err != nil {
+		slog.Error("Failed to update webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send posts the signed payload and treats any non-2xx response as an error
+func (d *Dispatcher) send(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "URLCrawler-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(string(webhook.Secret), timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff doubles the retry delay per attempt, with jitter in the upper half
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.RetryDelay << (attempts - 1)
+	if delay <= 0 || delay > 6*time.Hour {
+		delay = 6 * time.Hour
+	}
+	half := delay / 2
+	return half + time.Duration(mathrand.Int63n(int64(half)+1))
+}
+
+// Sign computes the signature header value for a payload. Receivers should
+// recompute it with their secret and reject stale timestamps.
+func Sign(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a random signing secret
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
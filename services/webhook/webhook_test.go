@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	const secret, timestamp, payload = "secret", "1700000000", `{"event":"test"}`
+
+	// Independently computed: HMAC-SHA256("secret", "1700000000.{\"event\":\"test\"}")
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign(secret, timestamp, payload); got != want {
+		t.Fatalf("Sign() = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		name                       string
+		secret, timestamp, payload string
+	}{
+		{name: "other secret", secret: "other", timestamp: timestamp, payload: payload},
+		{name: "other timestamp", secret: secret, timestamp: "1700000001", payload: payload},
+		{name: "other payload", secret: secret, timestamp: timestamp, payload: `{"event":"tampered"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, tt.payload); got == want {
+				t.Errorf("Sign() = %s, want a different signature", got)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	second, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	if len(first) != 64 || strings.Trim(first, "0123456789abcdef") != "" {
+		t.Errorf("GenerateSecret() = %q, want 64 hex characters", first)
+	}
+	if first == second {
+		t.Errorf("GenerateSecret() returned the same secret twice")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{config: &Config{RetryDelay: 30 * time.Second}}
+
+	tests := []struct {
+		attempts int
+		delay    time.Duration // Before jitter
+	}{
+		{attempts: 1, delay: 30 * time.Second},
+		{attempts: 2, delay: time.Minute},
+		{attempts: 5, delay: 8 * time.Minute},
+		{attempts: 10, delay: 256 * time.Minute},
+		{attempts: 11, delay: 6 * time.Hour}, // Capped
+		{attempts: 64, delay: 6 * time.Hour}, // Shift overflow falls back to the cap
+	}
+
+	for _, tt := range tests {
+		for range 100 {
+			if got := d.backoff(tt.attempts); got < tt.delay/2 || got > tt.delay {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempts, got, tt.delay/2, tt.delay)
+			}
+		}
+	}
+}
@@ -3,104 +3,108 @@ package crawl_manager
 import (
 	"fmt"
 	"sykell-challenge/backend/models"
-	"sykell-challenge/backend/services/socket"
+	"sykell-challenge/backend/services/events"
 	crawlUtils "sykell-challenge/backend/utils/crawl"
+	"time"
 )
 
-type SocketMessage struct {
-	JobID       string       `json:"jobId"`
-	URL         string       `json:"url"`
-	URLID       string       `json:"urlId"`
-	Status      string       `json:"status"`
-	StartedAt   string       `json:"startedAt,omitempty"`
-	CompletedAt string       `json:"completedAt,omitempty"`
-	Progress    int          `json:"progress,omitempty"`
-	Title       string       `json:"title,omitempty"`
-	StatusCode  int          `json:"statusCode,omitempty"`
-	HTMLVersion string       `json:"htmlVersion,omitempty"`
-	LoginForm   bool         `json:"loginForm,omitempty"`
-	LinksCount  int          `json:"linksCount,omitempty"`
-	TagsCount   int          `json:"tagsCount,omitempty"`
-	Tags        models.Tags  `json:"tags,omitempty"`
-	Links       models.Links `json:"links,omitempty"`
-	Error       string       `json:"error,omitempty"`
+// jobPayload fills the fields every crawl event carries
+func jobPayload(job models.CrawlJob, status string) events.Payload {
+	return events.Payload{
+		JobID:  fmt.Sprintf("%d", job.ID),
+		URL:    job.URL,
+		URLID:  fmt.Sprintf("%d", job.URLID),
+		Status: status,
+	}
 }
 
-func BroadcastJobQueued(jobID, url string, urlID uint) {
-	socket.BroadcastCrawlUpdate("crawl_queued", SocketMessage{
-		JobID:  jobID,
-		URL:    url,
-		Status: "queued",
-		URLID:  fmt.Sprintf("%d", urlID),
+func publish(eventType string, job models.CrawlJob, payload events.Payload) {
+	events.Publish(events.Event{
+		Type:    eventType,
+		UserID:  job.UserID,
+		Payload: payload,
 	})
 }
 
+func BroadcastJobQueued(job models.CrawlJob) {
+	publish(events.CrawlQueued, job, jobPayload(job, "queued"))
+}
+
 func BroadcastJobStarted(job models.CrawlJob) {
-	socket.BroadcastCrawlUpdate("crawl_started", SocketMessage{
-		JobID:     fmt.Sprintf("%d", job.Model.ID),
-		URL:       job.URL,
-		URLID:     fmt.Sprintf("%d", job.URLID),
-		Status:    job.Status,
-		StartedAt: job.StartedAt.Format("2006-01-02 15:04:05"),
-		Progress:  job.Progress,
-	})
+	payload := jobPayload(job, job.Status)
+	payload.StartedAt = job.StartedAt.Format("2006-01-02 15:04:05")
+	payload.Progress = job.Progress
+	publish(events.CrawlStarted, job, payload)
 }
 
 func BroadcastHalfCompleted(job models.CrawlJob, crawlData crawlUtils.CrawlData) {
-	fmt.Printf("Broadcasting half-completed job: %s", job.ID)
-	fmt.Printf("Job details: %+v", job)
-	fmt.Printf("Crawl data: %+v", crawlData)
-	socket.BroadcastCrawlUpdate("crawl_half_completed", SocketMessage{
-		JobID:       fmt.Sprintf("%d", job.ID),
-		URL:         job.URL,
-		URLID:       fmt.Sprintf("%d", job.URLID),
-		Status:      job.Status,
-		StartedAt:   job.StartedAt.Format("2006-01-02 15:04:05"),
-		Progress:    job.Progress,
-		Title:       crawlData.MainData.Title,
-		StatusCode:  crawlData.MainData.StatusCode,
-		HTMLVersion: crawlData.MainData.HTMLVersion,
-		LoginForm:   crawlData.MainData.LoginForm,
-		TagsCount:   len(crawlData.MainData.Tags),
-		Tags:        crawlData.MainData.Tags,
-	})
+	payload := jobPayload(job, job.Status)
+	payload.StartedAt = job.StartedAt.Format("2006-01-02 15:04:05")
+	payload.Progress = job.Progress
+	payload.Title = crawlData.MainData.Title
+	payload.StatusCode = crawlData.MainData.StatusCode
+	payload.HTMLVersion = crawlData.MainData.HTMLVersion
+	payload.LoginForm = crawlData.MainData.LoginForm
+	payload.Forms = crawlData.MainData.Forms
+	payload.AuthScheme = crawlData.MainData.AuthScheme
+	payload.TagsCount = len(crawlData.MainData.Tags)
+	payload.Tags = crawlData.MainData.Tags
+	publish(events.CrawlHalfCompleted, job, payload)
+}
+
+func BroadcastProgress(job models.CrawlJob, progress models.CrawlJob) {
+	payload := jobPayload(job, "running")
+	payload.Progress = progress.Progress
+	payload.Counts = &events.Counts{
+		PagesFetched:    progress.PagesFetched,
+		LinksDiscovered: progress.LinksDiscovered,
+		LinksChecked:    progress.LinksChecked,
+		BrokenLinks:     progress.BrokenLinks,
+		LinksRemaining:  progress.LinksRemaining,
+		ETASeconds:      progress.ETASeconds,
+	}
+	publish(events.CrawlProgress, job, payload)
 }
 
 func BroadcastCompleted(job models.CrawlJob, crawlData crawlUtils.CrawlData) {
-	socket.BroadcastCrawlUpdate("crawl_completed", SocketMessage{
-		JobID:       fmt.Sprintf("%d", job.ID),
-		URL:         job.URL,
-		URLID:       fmt.Sprintf("%d", job.URLID),
-		Status:      job.Status,
-		StartedAt:   job.StartedAt.Format("2006-01-02 15:04:05"),
-		CompletedAt: job.CompletedAt.Format("2006-01-02 15:04:05"),
-		Progress:    job.Progress,
-		Title:       crawlData.MainData.Title,
-		StatusCode:  crawlData.MainData.StatusCode,
-		HTMLVersion: crawlData.MainData.HTMLVersion,
-		LoginForm:   crawlData.MainData.LoginForm,
-		LinksCount:  crawlData.LinkCount,
-		TagsCount:   len(crawlData.MainData.Tags),
-		Tags:        crawlData.MainData.Tags,
-		Links:       crawlData.MainData.Links,
-	})
+	payload := jobPayload(job, job.Status)
+	payload.StartedAt = job.StartedAt.Format("2006-01-02 15:04:05")
+	payload.CompletedAt = job.CompletedAt.Format("2006-01-02 15:04:05")
+	payload.Progress = job.Progress
+	payload.Title = crawlData.MainData.Title
+	payload.StatusCode = crawlData.MainData.StatusCode
+	payload.HTMLVersion = crawlData.MainData.HTMLVersion
+	payload.LoginForm = crawlData.MainData.LoginForm
+	payload.Forms = crawlData.MainData.Forms
+	payload.AuthScheme = crawlData.MainData.AuthScheme
+	payload.LinksCount = crawlData.LinkCount
+	payload.TagsCount = len(crawlData.MainData.Tags)
+	payload.Tags = crawlData.MainData.Tags
+	payload.Links = crawlData.MainData.Links
+	publish(events.CrawlCompleted, job, payload)
 }
 
 func BroadcastError(job models.CrawlJob, errorMsg string) {
-	socket.BroadcastCrawlUpdate("crawl_error", SocketMessage{
-		JobID:  fmt.Sprintf("%d", job.ID),
-		URL:    job.URL,
-		URLID:  fmt.Sprintf("%d", job.URLID),
-		Error:  errorMsg,
-		Status: "error",
-	})
+	payload := jobPayload(job, "error")
+	payload.Error = errorMsg
+	publish(events.CrawlError, job, payload)
+}
+
+func BroadcastRetrying(job models.CrawlJob, maxAttempts int, delay time.Duration, reason string) {
+	payload := jobPayload(job, job.Status)
+	payload.Error = reason
+	payload.Attempt = job.Attempts
+	payload.MaxAttempts = maxAttempts
+	payload.RetryIn = delay.Seconds()
+	publish(events.CrawlRetrying, job, payload)
+}
+
+func BroadcastPaused(job models.CrawlJob) {
+	payload := jobPayload(job, "paused")
+	payload.Progress = job.Progress
+	publish(events.CrawlPaused, job, payload)
 }
 
 func BroadcastCancelled(job models.CrawlJob) {
-	socket.BroadcastCrawlUpdate("crawl_cancelled", SocketMessage{
-		JobID:  fmt.Sprintf("%d", job.ID),
-		URL:    job.URL,
-		URLID:  fmt.Sprintf("%d", job.URLID),
-		Status: "cancelled",
-	})
+	publish(events.CrawlCancelled, job, jobPayload(job, "cancelled"))
 }
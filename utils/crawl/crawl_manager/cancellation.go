@@ -0,0 +1,69 @@
+package crawl_manager
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// contextTransport ties every outbound request to the crawl's context so
+// cancelling the job aborts requests in flight, including body downloads
+type contextTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Keep the request's own deadline and additionally cancel it with the crawl
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+
+	// The collector's requests carry no trace; record them under the crawl's span
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithSpan(ctx, trace.SpanFromContext(t.ctx))
+	}
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody unregisters a request from the crawl's context once its body
+// is closed, so finished requests do not pile up on long crawls
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// bindContext makes all further requests of this crawl stop when ctx ends
+func (cm *CrawlManager) bindContext(ctx context.Context) {
+	cm.ctx = ctx
+	if cm.cancelable != nil {
+		cm.cancelable.ctx = ctx
+	}
+}
+
+// cancelled reports whether the crawl's context has ended
+func (cm *CrawlManager) cancelled() bool {
+	return cm.ctx.Err() != nil
+}
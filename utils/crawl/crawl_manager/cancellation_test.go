@@ -0,0 +1,82 @@
+package crawl_manager
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestContextTransportReleasesRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error // Returned by the base transport
+	}{
+		{name: "body closed"},
+		{name: "round trip failed", err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crawlCtx, cancelCrawl := context.WithCancel(context.Background())
+			defer cancelCrawl()
+
+			var requestCtx context.Context
+			transport := &contextTransport{ctx: crawlCtx, base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				requestCtx = req.Context()
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+			})}
+
+			req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+			resp, err := transport.RoundTrip(req)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("RoundTrip() error = %v, want %v", err, tt.err)
+			}
+			if resp != nil {
+				if requestCtx.Err() != nil {
+					t.Fatalf("request context ended before the body was closed")
+				}
+				resp.Body.Close()
+			}
+
+			if requestCtx.Err() == nil {
+				t.Errorf("request context still registered on the crawl context")
+			}
+		})
+	}
+}
+
+func TestContextTransportCancelsWithCrawl(t *testing.T) {
+	crawlCtx, cancelCrawl := context.WithCancel(context.Background())
+
+	var requestCtx context.Context
+	transport := &contextTransport{ctx: crawlCtx, base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requestCtx = req.Context()
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	})}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	cancelCrawl()
+	<-requestCtx.Done()
+
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip() after cancel error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,54 @@
+package crawl_manager
+
+import (
+	"slices"
+	"time"
+
+	"sykell-challenge/backend/models"
+)
+
+// Checkpoint captures the state of a stopped crawl so it can be resumed;
+// nil if the main page was not analyzed yet. Only call it once Crawl returned.
+func (cm *CrawlManager) Checkpoint() *models.CrawlCheckpoint {
+	if !cm.pageDone {
+		return nil
+	}
+
+	cm.progress.mu.Lock()
+	defer cm.progress.mu.Unlock()
+
+	page := *cm.data
+	page.Links = slices.Clone(cm.data.Links)
+
+	return &models.CrawlCheckpoint{
+		Page:            page,
+		PendingLinks:    slices.Clone(cm.pending),
+		LinksDiscovered: cm.progress.linksDiscovered,
+		BrokenLinks:     cm.progress.brokenLinks,
+		Outcome:         cm.outcome,
+		OutcomeReason:   cm.outcomeReason,
+		PausedAt:        time.Now(),
+	}
+}
+
+// Restore continues from a checkpoint: the page is not fetched again and
+// only the pending links are checked
+func (cm *CrawlManager) Restore(checkpoint models.CrawlCheckpoint) {
+	page := checkpoint.Page
+	cm.data = &page
+	cm.pending = slices.Clone(checkpoint.PendingLinks)
+	cm.outcome = checkpoint.Outcome
+	cm.outcomeReason = checkpoint.OutcomeReason
+	cm.pageDone = true
+
+	cm.progress.mu.Lock()
+	defer cm.progress.mu.Unlock()
+
+	cm.progress.pagesFetched = 1
+	cm.progress.linksDiscovered = checkpoint.LinksDiscovered
+	cm.progress.linksChecked = len(page.Links)
+	cm.progress.checkedBefore = len(page.Links)
+	cm.progress.linksPlanned = len(page.Links) + len(cm.pending)
+	cm.progress.brokenLinks = checkpoint.BrokenLinks
+	cm.progress.checkStartedAt = time.Now()
+}
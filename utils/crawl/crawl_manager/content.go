@@ -0,0 +1,73 @@
+package crawl_manager
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gocolly/colly"
+	"golang.org/x/net/html/charset"
+)
+
+// processContent records the MIME type and size of a response, decodes HTML
+// bodies to UTF-8 and reports whether the HTML analyzers should run on it
+func (cm *CrawlManager) processContent(r *colly.Response) bool {
+	header := r.Headers.Get("Content-Type")
+	mediaType, params, err := mime.ParseMediaType(header)
+	if err != nil || mediaType == "" || mediaType == "application/octet-stream" {
+		// Missing or generic header: sniff the first 512 bytes instead
+		header = http.DetectContentType(r.Body)
+		mediaType, params, _ = mime.ParseMediaType(header)
+	}
+
+	cm.data.ContentType = mediaType
+	cm.data.ContentLength = int64(len(r.Body))
+	declaredLength, err := strconv.ParseInt(r.Headers.Get("Content-Length"), 10, 64)
+	if err == nil && declaredLength > cm.data.ContentLength {
+		cm.data.ContentLength = declaredLength
+	}
+	cm.checkBodyLimit(int64(len(r.Body)), declaredLength)
+
+	if !isHTMLMediaType(mediaType) {
+		cm.logger.Debug("Skipping HTML analysis", "content_type", mediaType)
+		return false
+	}
+
+	// Colly already transcodes bodies whose header declares a charset, so only
+	// fall back to BOM and <meta charset> detection when the header has none
+	if _, declared := params["charset"]; !declared {
+		decoded, name, err := decodeToUTF8(r.Body, header)
+		if err != nil {
+			cm.logger.Warn("Failed to decode body", "charset", name, "error", err)
+		} else {
+			r.Body = decoded
+		}
+	}
+
+	// Colly only runs OnHTML callbacks when the header says html
+	r.Headers.Set("Content-Type", mediaType+"; charset=utf-8")
+
+	return true
+}
+
+// decodeToUTF8 converts body to UTF-8 using its BOM or <meta charset>. A body
+// without either that is already valid UTF-8 is kept as is, since the
+// windows-1252 fallback would garble it.
+func decodeToUTF8(body []byte, contentType string) ([]byte, string, error) {
+	encoding, name, certain := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" || (!certain && utf8.Valid(body)) {
+		return body, name, nil
+	}
+
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, name, err
+	}
+	return decoded, name, nil
+}
+
+func isHTMLMediaType(mediaType string) bool {
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml" || strings.HasSuffix(mediaType, "+html")
+}
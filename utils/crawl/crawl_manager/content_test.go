@@ -0,0 +1,73 @@
+package crawl_manager
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeToUTF8(t *testing.T) {
+	// Pushes the non-ASCII text past the 1 KB that charset detection looks at
+	padding := "<!-- " + strings.Repeat("x", 1100) + " -->"
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        string
+	}{
+		{
+			name:        "undeclared UTF-8",
+			body:        "<html><body>Zürich</body></html>",
+			contentType: "text/html",
+			want:        "<html><body>Zürich</body></html>",
+		},
+		{
+			name:        "undeclared UTF-8 with an ASCII-only first kilobyte",
+			body:        "<html><head>" + padding + "</head><body>Zürich</body></html>",
+			contentType: "text/html",
+			want:        "<html><head>" + padding + "</head><body>Zürich</body></html>",
+		},
+		{
+			name:        "windows-1252 declared in meta",
+			body:        "<html><head><meta charset=\"windows-1252\"></head><body>Z\xfcrich \x80</body></html>",
+			contentType: "text/html",
+			want:        "<html><head><meta charset=\"windows-1252\"></head><body>Zürich €</body></html>",
+		},
+		{
+			name:        "ISO-8859-1 declared in meta http-equiv",
+			body:        "<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=ISO-8859-1\"></head><body>Z\xfcrich</body></html>",
+			contentType: "text/html",
+			want:        "<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=ISO-8859-1\"></head><body>Zürich</body></html>",
+		},
+		{
+			name:        "ISO-8859-1 declared in the content type",
+			body:        "<html><body>Z\xfcrich</body></html>",
+			contentType: "text/html; charset=iso-8859-1",
+			want:        "<html><body>Zürich</body></html>",
+		},
+		{
+			name:        "undeclared Latin-1 falls back to windows-1252",
+			body:        "<html><body>Z\xfcrich</body></html>",
+			contentType: "text/html",
+			want:        "<html><body>Zürich</body></html>",
+		},
+		{
+			name:        "UTF-16 byte order mark",
+			body:        "\xff\xfeZ\x00\xfc\x00r\x00i\x00c\x00h\x00",
+			contentType: "text/html",
+			want:        "\ufeffZürich", // The HTML parser skips the BOM
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := decodeToUTF8([]byte(tt.body), tt.contentType)
+			if err != nil {
+				t.Fatalf("decodeToUTF8() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("decodeToUTF8() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,41 +1,67 @@
 package crawl_manager
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"sykell-challenge/backend/db"
 	"sykell-challenge/backend/models"
 	"sykell-challenge/backend/repositories"
 	"sykell-challenge/backend/utils"
 	crawlUtils "sykell-challenge/backend/utils/crawl"
+	"time"
 
 	"github.com/gocolly/colly"
 )
 
 type CrawlManager struct {
-	data        *models.URL
-	currentHost string
-	collector   *colly.Collector
-	linksFound  []string
-	urlRepo     *repositories.URLRepository
-	jobRepo     *repositories.CrawlJobRepository
+	data           *models.URL
+	currentHost    string
+	collector      *colly.Collector
+	linksFound     []string
+	options        models.CrawlOptions
+	loggedIn       bool
+	transport      http.RoundTripper // Shared by the collector and the link checker
+	setupErr       error
+	requests       *limitedTransport // Outbound request budget
+	cancelable     *contextTransport // Aborts requests when the crawl is cancelled
+	deadline       time.Time         // Total duration budget
+	outcome        string
+	outcomeReason  string
+	job            models.CrawlJob // Job the crawl reports progress for
+	jobID          string
+	ctx            context.Context
+	pending        []string // Links still to be checked
+	pageDone       bool     // Main page analyzed, or restored from a checkpoint
+	progress       progressTracker
+	urlRepo        *repositories.URLRepository
+	jobRepo        *repositories.CrawlJobRepository
+	credentialRepo *repositories.CredentialRepository
+	logger         *slog.Logger
 }
 
-func InitializeCrawlManager(url string) *CrawlManager {
+func InitializeCrawlManager(url string, options models.CrawlOptions) *CrawlManager {
 	var data models.URL
 	data.URL = url
 	data.Links = models.Links{}
 	data.Tags = models.Tags{}
+	data.Forms = models.Forms{}
 
 	db := db.GetDB()
 	urlRepo := repositories.NewURLRepository(db)
 	jobRepo := repositories.NewCrawlJobRepository(db)
 
 	cm := &CrawlManager{
-		data:        &data,
-		currentHost: utils.GetHostFromURL(url),
-		linksFound:  []string{},
-		urlRepo:     urlRepo,
-		jobRepo:     jobRepo,
+		data:           &data,
+		currentHost:    utils.GetHostFromURL(url),
+		linksFound:     []string{},
+		options:        options,
+		ctx:            context.Background(),
+		urlRepo:        urlRepo,
+		jobRepo:        jobRepo,
+		credentialRepo: repositories.NewCredentialRepository(db),
+		logger:         slog.Default(),
 	}
 
 	cm.initCrawler()
@@ -43,49 +69,104 @@ func InitializeCrawlManager(url string) *CrawlManager {
 	return cm
 }
 
-func (cm *CrawlManager) Crawl() crawlUtils.CrawlData {
-	cm.collector.Visit(cm.data.URL)
+// SetJob ties the crawl to its CrawlJob so progress can be reported
+func (cm *CrawlManager) SetJob(job models.CrawlJob) {
+	cm.job = job
+	cm.jobID = fmt.Sprint(job.ID)
+}
 
-	cm.collector.Wait()
+// SetLogger sets the logger for the crawl's messages, usually one carrying the job's identifiers
+func (cm *CrawlManager) SetLogger(logger *slog.Logger) {
+	cm.logger = logger
+}
 
-	if err := cm.urlRepo.Update(cm.data); err != nil {
-		fmt.Println("crawl_manager.go:52 Tried to update URL record:", cm.data)
-		fmt.Println("crawl_manager.go:53 Failed to update URL record: ", err)
-		BroadcastError(models.CrawlJob{
-			URL:   cm.data.URL,
-			URLID: cm.data.ID,
-		}, "Failed to update URL record: "+err.Error())
-		return crawlUtils.CrawlData{}
+// Crawl fetches the page and checks its links; cancelling ctx aborts all
+// outbound requests and nothing is persisted afterwards. A crawl restored
+// from a checkpoint skips the page fetch and only checks the pending links.
+func (cm *CrawlManager) Crawl(ctx context.Context) (crawlUtils.CrawlData, error) {
+	if cm.setupErr != nil {
+		return crawlUtils.CrawlData{}, cm.setupErr
+	}
+	cm.bindContext(ctx)
+
+	// Progress and results are still written while the crawl stops
+	queryCtx := context.WithoutCancel(ctx)
+	cm.urlRepo = cm.urlRepo.WithContext(queryCtx)
+	cm.jobRepo = cm.jobRepo.WithContext(queryCtx)
+
+	cm.startClock()
+
+	if cm.options.Login != nil {
+		if err := cm.traceStep(ctx, "crawl.login", func(context.Context) error { return cm.performLogin() }); err != nil {
+			if cm.cancelled() {
+				return crawlUtils.CrawlData{}, ctx.Err()
+			}
+			return crawlUtils.CrawlData{}, fmt.Errorf("login step failed: %w", err)
+		}
+	}
+
+	if !cm.pageDone {
+		if err := cm.traceStep(ctx, "crawl.fetch_page", cm.fetchPage); err != nil {
+			return crawlUtils.CrawlData{}, err
+		}
+		cm.planLinks()
+		cm.pageDone = true
 	}
 
-	fmt.Printf("✅ Successfully updated URL record:\n%+v\n", cm.data)
+	cm.traceStep(ctx, "crawl.check_links", func(context.Context) error {
+		cm.checkLinks()
+		return nil
+	})
+	if cm.cancelled() {
+		return crawlUtils.CrawlData{}, ctx.Err()
+	}
+	cm.reportProgress(true)
 
-	cm.jobRepo.UpdateStatus(cm.data.JobId, "running")
+	outcome := cm.outcome
+	if outcome == "" {
+		outcome = crawlUtils.OutcomeCompleted
+	}
 
-	fmt.Printf("✅ Successfully updated job status to 'running' for Job ID: %s\n", cm.data.JobId)
-	cm.jobRepo.UpdateProgress(cm.data.JobId, 75)
-	fmt.Printf("✅ Successfully updated job progress to 75%% for Job ID: %s\n", cm.data.JobId)
+	return crawlUtils.CrawlData{
+		MainData:      *cm.data,
+		LinkCount:     len(cm.data.Links),
+		Outcome:       outcome,
+		OutcomeReason: cm.outcomeReason,
+	}, nil
+}
+
+// fetchPage visits and analyzes the main page and stores the intermediate result
+func (cm *CrawlManager) fetchPage(ctx context.Context) error {
+	if err := cm.collector.Visit(cm.data.URL); err != nil {
+		if cm.cancelled() {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to fetch %s: %w", cm.data.URL, err)
+	}
 
-	currentJob, err := cm.jobRepo.GetByID(cm.data.JobId)
+	cm.collector.Wait()
+
+	if cm.cancelled() {
+		return ctx.Err()
+	}
+
+	if err := cm.urlRepo.Update(cm.data); err != nil {
+		return fmt.Errorf("failed to update URL record: %w", err)
+	}
+
+	cm.reportProgress(true)
+
+	currentJob, err := cm.jobRepo.GetByID(cm.jobID)
 	if err != nil {
-		BroadcastError(models.CrawlJob{
-			URL:   cm.data.URL,
-			URLID: cm.data.ID,
-		}, "Failed to retrieve current job: "+err.Error())
-		return crawlUtils.CrawlData{}
+		return fmt.Errorf("failed to retrieve current job: %w", err)
 	}
 
-	fmt.Printf("Successfully retrieved current job: %+v\n", currentJob)
+	cm.logger.Debug("Main page analyzed", "status_code", cm.data.StatusCode, "links", len(cm.data.Links))
 
 	BroadcastHalfCompleted(*currentJob, crawlUtils.CrawlData{
 		MainData:  *cm.data,
 		LinkCount: len(cm.data.Links),
 	})
 
-	cm.processLinks()
-
-	return crawlUtils.CrawlData{
-		MainData:  *cm.data,
-		LinkCount: len(cm.data.Links),
-	}
+	return nil
 }
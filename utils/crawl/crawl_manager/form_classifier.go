@@ -0,0 +1,143 @@
+package crawl_manager
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	FormTypeLogin         = "login"
+	FormTypeSignup        = "signup"
+	FormTypePasswordReset = "password_reset"
+	FormTypeSearch        = "search"
+	FormTypeNewsletter    = "newsletter"
+	FormTypeOther         = "other"
+)
+
+var (
+	loginKeywords      = []string{"login", "log in", "log-in", "signin", "sign in", "sign-in", "anmelden", "einloggen"}
+	signupKeywords     = []string{"signup", "sign up", "sign-up", "register", "registration", "create account", "create an account", "join", "registrieren"}
+	resetKeywords      = []string{"forgot", "reset", "recover", "change password", "new password", "passwort vergessen"}
+	searchKeywords     = []string{"search", "suche", "query"}
+	newsletterKeywords = []string{"newsletter", "subscribe", "subscription", "mailing list", "abonnieren"}
+
+	// oauthProviders maps URL fragments of common identity providers' authorization endpoints
+	oauthProviders = []string{
+		"accounts.google.com/o/oauth2",
+		"facebook.com/dialog/oauth",
+		"github.com/login/oauth",
+		"login.microsoftonline.com",
+		"appleid.apple.com/auth",
+		"api.twitter.com/oauth",
+		"linkedin.com/oauth",
+		"/oauth/authorize",
+		"/oauth2/authorize",
+	}
+	oauthButtonPhrases = []string{"sign in with", "log in with", "login with", "continue with", "connect with", "anmelden mit"}
+	// oauthProviderNames must appear in a button's label or link along with a phrase,
+	// so that buttons such as "Continue with checkout" are not taken for sign-ins
+	oauthProviderNames = map[string]bool{
+		"google": true, "facebook": true, "github": true, "gitlab": true, "microsoft": true,
+		"apple": true, "twitter": true, "linkedin": true, "amazon": true, "slack": true, "discord": true,
+	}
+)
+
+// classifyForm inspects a <form> selection and returns one of the FormType* values
+func classifyForm(form *goquery.Selection) string {
+	passwordFields := form.Find("input[type='password']")
+	text := formFingerprint(form)
+
+	if passwordFields.Length() > 0 {
+		newPassword := form.Find("input[autocomplete='new-password']").Length()
+		currentPassword := form.Find("input[autocomplete='current-password']").Length()
+
+		// A current + new password pair is a change-password form
+		if currentPassword > 0 && newPassword > 0 {
+			return FormTypePasswordReset
+		}
+		if containsAny(text, resetKeywords) && !containsAny(text, loginKeywords) {
+			return FormTypePasswordReset
+		}
+		// Password confirmation fields or explicit new-password hints indicate a signup
+		if passwordFields.Length() > 1 || newPassword > 0 || containsAny(text, signupKeywords) {
+			return FormTypeSignup
+		}
+		return FormTypeLogin
+	}
+
+	if form.Find("input[type='search']").Length() > 0 || form.Is("[role='search']") {
+		return FormTypeSearch
+	}
+
+	hasEmail := form.Find("input[type='email'], input[name*='email'], input[name*='mail']").Length() > 0
+	if hasEmail && containsAny(text, resetKeywords) {
+		return FormTypePasswordReset
+	}
+	if hasEmail && containsAny(text, newsletterKeywords) {
+		return FormTypeNewsletter
+	}
+	if form.Find("input[name='q'], input[name='s'], input[name='query'], input[name='search']").Length() > 0 ||
+		containsAny(text, searchKeywords) {
+		return FormTypeSearch
+	}
+
+	return FormTypeOther
+}
+
+// isOAuthButton reports whether a link or button starts a third-party sign-in flow
+func isOAuthButton(href, text string) bool {
+	href = strings.ToLower(href)
+	for _, provider := range oauthProviders {
+		if strings.Contains(href, provider) {
+			return true
+		}
+	}
+
+	text = strings.ToLower(text)
+	if !containsAny(text, oauthButtonPhrases) {
+		return false
+	}
+	for _, word := range strings.FieldsFunc(text+" "+href, isWordSeparator) {
+		if oauthProviderNames[word] {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// formFingerprint collects the lower-cased text that hints at a form's purpose:
+// the action URL, ids, classes, field names, placeholders and submit labels
+func formFingerprint(form *goquery.Selection) string {
+	var parts []string
+	for _, attr := range []string{"action", "id", "class", "name", "aria-label"} {
+		if value, ok := form.Attr(attr); ok {
+			parts = append(parts, value)
+		}
+	}
+
+	form.Find("input:not([type='hidden']), button, label, legend, h1, h2, h3, h4").Each(func(_ int, s *goquery.Selection) {
+		for _, attr := range []string{"name", "id", "placeholder", "value", "aria-label"} {
+			if value, ok := s.Attr(attr); ok {
+				parts = append(parts, value)
+			}
+		}
+		parts = append(parts, s.Text())
+	})
+
+	return strings.ToLower(strings.Join(parts, " "))
+}
+
+func containsAny(s string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(s, keyword) {
+			return true
+		}
+	}
+	return false
+}
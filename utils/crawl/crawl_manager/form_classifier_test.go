@@ -0,0 +1,95 @@
+package crawl_manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestIsOAuthButton(t *testing.T) {
+	tests := []struct {
+		name string
+		href string
+		text string
+		want bool
+	}{
+		{name: "provider authorization URL", href: "https://accounts.google.com/o/oauth2/v2/auth?client_id=1", text: "Go", want: true},
+		{name: "generic authorization endpoint", href: "https://id.example.com/oauth/authorize", want: true},
+		{name: "phrase with provider name", text: "Sign in with Google", want: true},
+		{name: "continue with provider", text: "  Continue with Apple ", want: true},
+		{name: "connect with provider", text: "Connect with LinkedIn", want: true},
+		{name: "phrase with provider in link", href: "/auth/github/start", text: "Continue with your account", want: true},
+		{name: "German phrase", text: "Anmelden mit Microsoft", want: true},
+		{name: "continue with checkout", href: "/checkout", text: "Continue with checkout", want: false},
+		{name: "continue with email", text: "Continue with email", want: false},
+		{name: "connect with us", href: "/contact", text: "Connect with us", want: false},
+		{name: "provider name inside another word", text: "Continue with applesauce", want: false},
+		{name: "provider without phrase", href: "https://github.com/acme", text: "GitHub", want: false},
+		{name: "plain login", href: "/login", text: "Log in", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOAuthButton(tt.href, tt.text); got != tt.want {
+				t.Errorf("isOAuthButton(%q, %q) = %t, want %t", tt.href, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyForm(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "login",
+			html: `<form action="/session"><input name="user"><input type="password" name="pass"><button>Sign in</button></form>`,
+			want: FormTypeLogin,
+		},
+		{
+			name: "signup with confirmation",
+			html: `<form><input type="email"><input type="password"><input type="password"></form>`,
+			want: FormTypeSignup,
+		},
+		{
+			name: "change password",
+			html: `<form><input type="password" autocomplete="current-password"><input type="password" autocomplete="new-password"></form>`,
+			want: FormTypePasswordReset,
+		},
+		{
+			name: "forgot password",
+			html: `<form action="/forgot"><input type="email" name="email"><button>Reset</button></form>`,
+			want: FormTypePasswordReset,
+		},
+		{
+			name: "search",
+			html: `<form action="/find"><input name="q"></form>`,
+			want: FormTypeSearch,
+		},
+		{
+			name: "newsletter",
+			html: `<form><input type="email" name="email"><button>Subscribe</button></form>`,
+			want: FormTypeNewsletter,
+		},
+		{
+			name: "other",
+			html: `<form><textarea name="message"></textarea><button>Send</button></form>`,
+			want: FormTypeOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := classifyForm(doc.Find("form").First()); got != tt.want {
+				t.Errorf("classifyForm() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
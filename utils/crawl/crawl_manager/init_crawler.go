@@ -1,7 +1,7 @@
 package crawl_manager
 
 import (
-	"fmt"
+	"net/http"
 	"strings"
 	"sykell-challenge/backend/models"
 
@@ -12,7 +12,10 @@ func (cm *CrawlManager) initCrawler() {
 
 	cm.collector = colly.NewCollector()
 
-	cm.collector.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3"
+	cm.applyOptions()
+
+	// Parse 4xx/5xx pages too so auth walls and their login forms are analyzed
+	cm.collector.ParseHTTPErrorResponse = true
 
 	cm.collector.OnResponse(func(r *colly.Response) {
 		cm.ProcessMainResponse(r)
@@ -28,6 +31,14 @@ func (cm *CrawlManager) initCrawler() {
 		cm.ProcessForm(e)
 	})
 
+	cm.collector.OnHTML("input[type='password']", func(e *colly.HTMLElement) {
+		cm.ProcessStandalonePassword(e)
+	})
+
+	cm.collector.OnHTML("a[href], button", func(e *colly.HTMLElement) {
+		cm.ProcessOAuthButton(e)
+	})
+
 	cm.collector.OnHTML("title", func(e *colly.HTMLElement) {
 		cm.ProcessTitle(e)
 	})
@@ -36,41 +47,101 @@ func (cm *CrawlManager) initCrawler() {
 		cm.ProcessTag(e)
 	})
 
-	cm.collector.OnRequest(func(r *colly.Request) {
-		fmt.Println("Visiting URL: ", r.URL.String())
-	})
+	cm.collector.OnRequest(cm.abortIfCancelled)
 
 	cm.collector.OnError(func(r *colly.Response, err error) {
-		fmt.Println("Error visiting URL: ", r.Request.URL.String(), " - ", err)
+		cm.logger.Warn("Error visiting URL", "target", r.Request.URL.String(), "status_code", r.StatusCode, "error", err)
 	})
 }
 
+// abortIfCancelled stops requests once the crawl has been cancelled
+func (cm *CrawlManager) abortIfCancelled(r *colly.Request) {
+	if cm.cancelled() {
+		r.Abort()
+		return
+	}
+	cm.logger.Debug("Visiting URL", "target", r.URL.String())
+}
+
 // ProcessTag processes a single HTML tag element and updates the tag count
 func (cm *CrawlManager) ProcessTag(e *colly.HTMLElement) {
 	tagName := e.Name
 	cm.incrementTagCount(tagName)
 }
 
-// ProcessForm checks for login forms and updates the LoginForm field
+// ProcessForm classifies a form and updates the Forms and LoginForm fields
 func (cm *CrawlManager) ProcessForm(e *colly.HTMLElement) {
-	// Check if form contains password field (indicates login form)
-	passwordField := e.DOM.Find("input[type='password']")
-	if passwordField.Length() > 0 {
-		cm.data.LoginForm = true
-		fmt.Println("Login form detected")
+	method := strings.ToUpper(strings.TrimSpace(e.Attr("method")))
+	if method == "" {
+		method = "GET"
+	}
+
+	form := models.Form{
+		Type:   classifyForm(e.DOM),
+		Action: e.Request.AbsoluteURL(e.Attr("action")),
+		Method: method,
+		Source: "form",
+	}
+	cm.addForm(form)
+	cm.logger.Debug("Form detected", "type", form.Type)
+}
+
+// ProcessStandalonePassword records login fields rendered outside of a <form> element
+func (cm *CrawlManager) ProcessStandalonePassword(e *colly.HTMLElement) {
+	if e.DOM.Closest("form").Length() > 0 {
+		return
+	}
+
+	cm.addForm(models.Form{
+		Type:   FormTypeLogin,
+		Action: e.Request.URL.String(),
+		Method: "GET", // Same default as a <form> without a method
+		Source: "standalone",
+	})
+}
+
+// ProcessOAuthButton records third-party sign-in links and buttons as login entry points
+func (cm *CrawlManager) ProcessOAuthButton(e *colly.HTMLElement) {
+	href := e.Attr("href")
+	if href == "" {
+		href = e.Attr("formaction")
+	}
+	if !isOAuthButton(href, e.Text) {
+		return
+	}
+
+	action := e.Request.URL.String()
+	if href != "" {
+		action = e.Request.AbsoluteURL(href)
 	}
+
+	cm.addForm(models.Form{
+		Type:   FormTypeLogin,
+		Action: action,
+		Method: "OAUTH",
+		Source: "oauth",
+	})
 }
 
 // ProcessTitle extracts and stores the page title
 func (cm *CrawlManager) ProcessTitle(e *colly.HTMLElement) {
 	title := e.Text
 	cm.data.Title = strings.TrimSpace(title) // Store the title and trim whitespace
-	fmt.Println("Title found: ", title)
+	cm.logger.Debug("Title found", "title", cm.data.Title)
 }
 
 // ProcessMainResponse handles the main URL response and detects HTML version
 func (cm *CrawlManager) ProcessMainResponse(r *colly.Response) {
 	cm.data.StatusCode = r.StatusCode
+	cm.pageFetched()
+	cm.processAuthChallenge(r)
+
+	if !cm.processContent(r) {
+		// Non-HTML responses only record MIME type, size and status
+		cm.data.HTMLVersion = ""
+		return
+	}
+
 	// Detect HTML version (4 or 5)
 	bodyStr := string(r.Body)
 	if strings.Contains(bodyStr, "<!DOCTYPE html>") {
@@ -101,6 +172,40 @@ func (cm *CrawlManager) incrementTagCount(tagName string) {
 	})
 }
 
+// addForm appends a form unless an identical one was already recorded
+func (cm *CrawlManager) addForm(form models.Form) {
+	for _, existing := range cm.data.Forms {
+		if existing == form {
+			return
+		}
+	}
+
+	cm.data.Forms = append(cm.data.Forms, form)
+	if form.Type == FormTypeLogin {
+		cm.data.LoginForm = true
+	}
+}
+
+// processAuthChallenge detects HTTP 401/403 auth walls and the advertised auth scheme
+func (cm *CrawlManager) processAuthChallenge(r *colly.Response) {
+	if r.StatusCode != http.StatusUnauthorized && r.StatusCode != http.StatusForbidden {
+		return
+	}
+	cm.data.AuthRequired = true
+
+	challenge := strings.TrimSpace(r.Headers.Get("WWW-Authenticate"))
+	if challenge == "" {
+		return
+	}
+
+	scheme, _, _ := strings.Cut(challenge, " ")
+	cm.data.AuthScheme = strings.ToLower(scheme)
+	if cm.data.AuthScheme == "basic" {
+		cm.data.LoginForm = true
+	}
+	cm.logger.Debug("Auth challenge detected", "scheme", cm.data.AuthScheme)
+}
+
 // shouldSkipLink checks if a link should be skipped
 func (cm *CrawlManager) shouldSkipLink(link string) bool {
 	return link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "javascript:")
@@ -0,0 +1,93 @@
+package crawl_manager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	crawlUtils "sykell-challenge/backend/utils/crawl"
+)
+
+var errRequestLimit = errors.New("outbound request limit reached")
+
+// limitedTransport fails requests once the job's outbound request budget is spent
+type limitedTransport struct {
+	base      http.RoundTripper
+	remaining atomic.Int64
+}
+
+func newLimitedTransport(base http.RoundTripper, limit int) *limitedTransport {
+	t := &limitedTransport{base: base}
+	t.remaining.Store(int64(limit))
+	return t
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.remaining.Add(-1) < 0 {
+		return nil, errRequestLimit
+	}
+	return t.base.RoundTrip(req)
+}
+
+func (t *limitedTransport) exhausted() bool {
+	return t.remaining.Load() <= 0
+}
+
+// applyLimits bounds body size and request timeouts for the collector
+func (cm *CrawlManager) applyLimits() {
+	limits := cm.options.Limits
+	cm.collector.MaxBodySize = int(limits.BodyLimit())
+
+	timeout := cm.options.Timeout()
+	if limits.DurationLimit() < timeout {
+		timeout = limits.DurationLimit()
+	}
+	cm.collector.SetRequestTimeout(timeout)
+}
+
+// startClock starts the job's total duration budget
+func (cm *CrawlManager) startClock() {
+	cm.deadline = time.Now().Add(cm.options.Limits.DurationLimit())
+}
+
+// remainingTime returns how much of the duration budget is left
+func (cm *CrawlManager) remainingTime() time.Duration {
+	return time.Until(cm.deadline)
+}
+
+// checkBudget stops link checking once the job ran out of time or requests
+func (cm *CrawlManager) checkBudget() bool {
+	if cm.remainingTime() <= 0 {
+		cm.setOutcome(crawlUtils.OutcomeTimeout, fmt.Sprintf("crawl exceeded the maximum duration of %s", cm.options.Limits.DurationLimit()))
+		return false
+	}
+	if cm.requests != nil && cm.requests.exhausted() {
+		cm.setOutcome(crawlUtils.OutcomeTruncated, fmt.Sprintf("crawl reached the limit of %d outbound requests", cm.options.Limits.RequestLimit()))
+		return false
+	}
+	return true
+}
+
+// checkBodyLimit flags responses cut off at the maximum body size
+func (cm *CrawlManager) checkBodyLimit(bodyLength, declaredLength int64) {
+	limit := cm.options.Limits.BodyLimit()
+	if bodyLength >= limit || declaredLength > limit {
+		cm.setOutcome(crawlUtils.OutcomeTruncated, fmt.Sprintf("response body exceeded the limit of %d bytes", limit))
+	}
+}
+
+// setOutcome records why a crawl ended early; a timeout takes precedence over truncation
+func (cm *CrawlManager) setOutcome(outcome, reason string) {
+	if cm.outcome == crawlUtils.OutcomeTimeout {
+		return
+	}
+	if cm.outcome == crawlUtils.OutcomeTruncated && outcome != crawlUtils.OutcomeTimeout {
+		return
+	}
+
+	cm.outcome = outcome
+	cm.outcomeReason = reason
+	cm.logger.Info("Crawl limit reached", "outcome", outcome, "reason", reason)
+}
@@ -1,27 +1,80 @@
 package crawl_manager
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 	"strings"
 	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/services/metrics"
 	"sykell-challenge/backend/utils"
+	crawlUtils "sykell-challenge/backend/utils/crawl"
+	"time"
 )
 
-func (cm *CrawlManager) processLinks() {
+// planLinks deduplicates the discovered links and caps them at the link limit
+func (cm *CrawlManager) planLinks() {
 	slices.Sort(cm.linksFound)
 
 	cm.linksFound = slices.Compact(cm.linksFound)
 
-	for _, link := range cm.linksFound {
+	linksToCheck := cm.linksFound
+	if limit := cm.options.Limits.LinkLimit(); len(linksToCheck) > limit {
+		cm.setOutcome(crawlUtils.OutcomeTruncated, fmt.Sprintf("checked %d of %d links (link limit)", limit, len(linksToCheck)))
+		linksToCheck = linksToCheck[:limit]
+	}
+	cm.pending = linksToCheck
+	cm.linksToCheck(len(cm.linksFound), len(linksToCheck))
+}
+
+// checkLinks checks the pending links, removing each one once its result is recorded
+func (cm *CrawlManager) checkLinks() {
+	for len(cm.pending) > 0 {
+		if cm.cancelled() || !cm.checkBudget() {
+			break
+		}
+		link := cm.pending[0]
+
 		if strings.HasPrefix(link, "/") {
 			link = cm.currentHost + link
 		}
 
-		if isAvailable, statusCode := utils.IsURLAvailable(link); isAvailable {
-			cm.data.Links = append(cm.data.Links, models.Link{Link: link, Type: cm.determineLinkType(link), StatusCode: statusCode})
+		linkType := cm.determineLinkType(link)
+		if linkType == "external" && !cm.options.ShouldCheckExternalLinks() {
+			cm.data.Links = append(cm.data.Links, models.Link{Link: link, Type: linkType})
+			cm.pending = cm.pending[1:]
+			cm.linkChecked(false)
+			continue
+		}
+
+		pingOptions := NewPingOptions(cm.options, linkType == "internal")
+		pingOptions.Transport = cm.transport
+		if remaining := cm.remainingTime(); remaining < pingOptions.Timeout {
+			pingOptions.Timeout = remaining
+		}
+		if cm.loggedIn && linkType == "internal" {
+			// Reuse the session established by the login step
+			pingOptions.Cookies = append(pingOptions.Cookies, cm.collector.Cookies(link)...)
+		}
+
+		checkStart := time.Now()
+		result := utils.PingURLContext(cm.ctx, link, pingOptions)
+		if cm.cancelled() {
+			// Aborted mid-check: leave the link pending
+			break
+		}
+		if errors.Is(result.Err, errRequestLimit) {
+			cm.checkBudget()
+			break
+		}
+		metrics.ObserveLinkCheck(time.Since(checkStart), result.StatusCode, result.Err)
+		if result.Available {
+			cm.data.Links = append(cm.data.Links, models.Link{Link: link, Type: linkType, StatusCode: result.StatusCode})
 		} else {
-			cm.data.Links = append(cm.data.Links, models.Link{Link: link, Type: "inaccessible", StatusCode: statusCode})
+			cm.data.Links = append(cm.data.Links, models.Link{Link: link, Type: "inaccessible", StatusCode: result.StatusCode})
 		}
+		cm.pending = cm.pending[1:]
+		cm.linkChecked(!result.Available)
 	}
 }
 
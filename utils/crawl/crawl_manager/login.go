@@ -0,0 +1,132 @@
+package crawl_manager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sykell-challenge/backend/models"
+
+	"github.com/gocolly/colly"
+	"gorm.io/gorm"
+)
+
+// performLogin submits the configured login form before the crawl. The login
+// collector shares the cookie jar with the main collector, so the session
+// cookies it receives are sent with the target crawl.
+func (cm *CrawlManager) performLogin() error {
+	step := cm.options.Login
+
+	// Only the submitter's own credentials may be used, whatever the stored options say
+	credential, err := cm.credentialRepo.GetByIDForUser(step.CredentialID, cm.job.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("login credential %d not found for the job's user", step.CredentialID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load login credential %d: %w", step.CredentialID, err)
+	}
+
+	// The clone shares the visited-URL store, so the target may be revisited after login
+	cm.collector.AllowURLRevisit = true
+	loginCollector := cm.collector.Clone()
+	// Clone copies no callbacks, so the login requests need the request hooks again
+	loginCollector.OnRequest(cm.applyRequestOptions)
+	loginCollector.OnRequest(cm.abortIfCancelled)
+
+	fields := map[string]string{}
+	actionURL := step.ActionURL
+	formFound := false
+
+	loginCollector.OnHTML("form", func(e *colly.HTMLElement) {
+		if formFound || e.DOM.Find(fmt.Sprintf("input[name=%q]", step.PasswordField)).Length() == 0 {
+			return
+		}
+		formFound = true
+
+		// Keep hidden inputs such as CSRF tokens
+		e.ForEach("input[type='hidden']", func(_ int, input *colly.HTMLElement) {
+			if name := input.Attr("name"); name != "" {
+				fields[name] = input.Attr("value")
+			}
+		})
+		if actionURL == "" {
+			actionURL = e.Request.AbsoluteURL(e.Attr("action"))
+		}
+	})
+
+	if err := loginCollector.Visit(step.FormURL); err != nil {
+		return fmt.Errorf("failed to load login form: %w", err)
+	}
+	loginCollector.Wait()
+
+	if actionURL == "" {
+		return errors.New("no form with the configured password field found on the login page")
+	}
+
+	for name, value := range step.ExtraFields {
+		fields[name] = value
+	}
+	fields[step.UsernameField] = credential.Username
+	fields[step.PasswordField] = string(credential.Password)
+
+	var response *colly.Response
+	loginCollector.OnResponse(func(r *colly.Response) {
+		response = r
+	})
+
+	cm.logger.Debug("Submitting login form", "action", actionURL)
+	if err := loginCollector.Post(actionURL, fields); err != nil {
+		return fmt.Errorf("failed to submit login form: %w", err)
+	}
+	loginCollector.Wait()
+
+	if err := cm.checkLoginSuccess(response); err != nil {
+		return err
+	}
+
+	cm.loggedIn = true
+	cm.logger.Info("Login step succeeded")
+	return nil
+}
+
+// checkLoginSuccess verifies the login response against the configured success check
+func (cm *CrawlManager) checkLoginSuccess(response *colly.Response) error {
+	if response == nil {
+		return errors.New("no response to login submission")
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("login submission returned HTTP %d", response.StatusCode)
+	}
+
+	check := cm.options.Login.SuccessCheck
+	body := string(response.Body)
+
+	if check.URLContains != "" && !strings.Contains(response.Request.URL.String(), check.URLContains) {
+		return errors.New("login did not redirect to the expected URL")
+	}
+	if check.TextContains != "" && !strings.Contains(body, check.TextContains) {
+		return errors.New("login response does not contain the expected text")
+	}
+	if check.TextNotContains != "" && strings.Contains(body, check.TextNotContains) {
+		return errors.New("login response contains the failure text")
+	}
+	if check.CookieName != "" && !hasCookie(cm.collector.Cookies(cm.data.URL), check.CookieName) {
+		return fmt.Errorf("login did not set the %q cookie", check.CookieName)
+	}
+
+	// Without explicit checks, treat a re-rendered password field as a failed login
+	if check == (models.LoginSuccessCheck{}) && strings.Contains(body, fmt.Sprintf("name=%q", cm.options.Login.PasswordField)) {
+		return errors.New("login form was shown again after submission")
+	}
+
+	return nil
+}
+
+func hasCookie(cookies []*http.Cookie, name string) bool {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return true
+		}
+	}
+	return false
+}
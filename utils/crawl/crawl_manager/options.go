@@ -0,0 +1,132 @@
+package crawl_manager
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/utils"
+	"sykell-challenge/backend/utils/egress"
+
+	"github.com/gocolly/colly"
+)
+
+const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3"
+
+// NewPingOptions converts crawl options into link checker options.
+// Credentials, cookies and custom headers are only included when withCredentials
+// is set, so they are never sent to third-party hosts.
+func NewPingOptions(options models.CrawlOptions, withCredentials bool) utils.PingURLOptions {
+	pingOptions := utils.DefaultPingOptions()
+	pingOptions.Timeout = options.Timeout()
+	pingOptions.MaxRedirects = options.RedirectLimit()
+	if options.UserAgent != "" {
+		pingOptions.UserAgent = options.UserAgent
+	}
+
+	if !withCredentials {
+		return pingOptions
+	}
+
+	pingOptions.Headers = options.Headers
+	for _, cookie := range options.Cookies {
+		pingOptions.Cookies = append(pingOptions.Cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	if options.BasicAuth != nil {
+		pingOptions.Username = options.BasicAuth.Username
+		pingOptions.Password = string(options.BasicAuth.Password)
+	}
+
+	return pingOptions
+}
+
+// NewTransport returns the outbound transport for a crawl's proxy settings,
+// shared by the page collector and the link checker. Crawls with the same
+// proxies reuse one transport and its connections.
+func NewTransport(options models.CrawlOptions) (http.RoundTripper, error) {
+	pool, err := egress.PoolFor(options.Proxies, options.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+	return egress.TransportFor(pool), nil
+}
+
+// applyOptions configures the collector from the crawl options
+func (cm *CrawlManager) applyOptions() {
+	transport, err := NewTransport(cm.options)
+	if err != nil {
+		// Never fall back to a direct connection when proxies were requested
+		cm.setupErr = fmt.Errorf("invalid proxy configuration: %w", err)
+		return
+	}
+	cm.requests = newLimitedTransport(transport, cm.options.Limits.RequestLimit())
+	cm.cancelable = &contextTransport{base: cm.requests, ctx: cm.ctx}
+	cm.transport = cm.cancelable
+	cm.collector.WithTransport(cm.transport)
+
+	cm.collector.UserAgent = defaultUserAgent
+	if cm.options.UserAgent != "" {
+		cm.collector.UserAgent = cm.options.UserAgent
+	}
+
+	cm.applyLimits()
+	cm.collector.RedirectHandler = cm.handleRedirect
+
+	if len(cm.options.Cookies) > 0 {
+		cookies := make([]*http.Cookie, 0, len(cm.options.Cookies))
+		for _, cookie := range cm.options.Cookies {
+			cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+		}
+		if err := cm.collector.SetCookies(cm.data.URL, cookies); err != nil {
+			cm.logger.Warn("Failed to set crawl cookies", "error", err)
+		}
+	}
+
+	cm.collector.OnRequest(cm.applyRequestOptions)
+}
+
+// applyRequestOptions adds the custom headers and credentials to a request,
+// which only go to the crawled host
+func (cm *CrawlManager) applyRequestOptions(r *colly.Request) {
+	if utils.GetHostFromURL(r.URL.String()) != cm.currentHost {
+		return
+	}
+	for name, value := range cm.options.Headers {
+		r.Headers.Set(name, value)
+	}
+	if cm.options.BasicAuth != nil {
+		credentials := cm.options.BasicAuth.Username + ":" + string(cm.options.BasicAuth.Password)
+		r.Headers.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	}
+}
+
+// handleRedirect enforces the redirect scheme and limit and mirrors colly's default
+// header forwarding, which is skipped once a RedirectHandler is set
+func (cm *CrawlManager) handleRedirect(req *http.Request, via []*http.Request) error {
+	if err := egress.CheckScheme(req.URL); err != nil {
+		return err
+	}
+	if cm.options.RedirectLimit() == 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) > cm.options.RedirectLimit() {
+		return fmt.Errorf("too many redirects (%d)", len(via))
+	}
+
+	lastRequest := via[len(via)-1]
+	for name, values := range lastRequest.Header {
+		for _, value := range values {
+			req.Header.Set(name, value)
+		}
+	}
+
+	// Never forward credentials or custom headers to a different host
+	if req.URL.Host != lastRequest.URL.Host {
+		req.Header.Del("Authorization")
+		for name := range cm.options.Headers {
+			req.Header.Del(name)
+		}
+	}
+
+	return nil
+}
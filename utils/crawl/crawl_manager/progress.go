@@ -0,0 +1,114 @@
+package crawl_manager
+
+import (
+	"sync"
+	"time"
+
+	"sykell-challenge/backend/models"
+)
+
+// progressInterval is the minimum time between two crawl_progress events for a job
+const progressInterval = time.Second
+
+// progressTracker counts fetched pages and checked links and reports them,
+// throttled, to the database and socket clients
+type progressTracker struct {
+	mu              sync.Mutex
+	pagesFetched    int
+	linksDiscovered int
+	linksPlanned    int // Discovered links that will be processed, after the link limit
+	linksChecked    int
+	brokenLinks     int
+	checkedBefore   int // Links checked before the crawl was resumed
+	checkStartedAt  time.Time
+	lastEmit        time.Time
+}
+
+// pageFetched records a fetched page
+func (cm *CrawlManager) pageFetched() {
+	cm.progress.mu.Lock()
+	cm.progress.pagesFetched++
+	cm.progress.mu.Unlock()
+
+	cm.reportProgress(false)
+}
+
+// linksToCheck records how many links were found and will be processed, and starts the ETA clock
+func (cm *CrawlManager) linksToCheck(discovered, planned int) {
+	cm.progress.mu.Lock()
+	cm.progress.linksDiscovered = discovered
+	cm.progress.linksPlanned = planned
+	cm.progress.checkStartedAt = time.Now()
+	cm.progress.mu.Unlock()
+
+	cm.reportProgress(true)
+}
+
+// linkChecked records a processed link and whether it turned out to be broken
+func (cm *CrawlManager) linkChecked(broken bool) {
+	cm.progress.mu.Lock()
+	cm.progress.linksChecked++
+	if broken {
+		cm.progress.brokenLinks++
+	}
+	cm.progress.mu.Unlock()
+
+	cm.reportProgress(false)
+}
+
+// reportProgress persists and broadcasts the current counts, at most once per
+// progressInterval unless force is set
+func (cm *CrawlManager) reportProgress(force bool) {
+	if cm.jobID == "" || cm.cancelled() {
+		return
+	}
+
+	cm.progress.mu.Lock()
+	if !force && time.Since(cm.progress.lastEmit) < progressInterval {
+		cm.progress.mu.Unlock()
+		return
+	}
+	cm.progress.lastEmit = time.Now()
+	update := cm.progress.snapshot()
+	cm.progress.mu.Unlock()
+
+	if err := cm.jobRepo.UpdateProgressCounts(cm.jobID, update); err != nil {
+		cm.logger.Warn("Failed to update job progress", "error", err)
+	}
+
+	BroadcastProgress(cm.job, update)
+}
+
+// snapshot returns the counts as a CrawlJob update; callers must hold mu
+func (p *progressTracker) snapshot() models.CrawlJob {
+	remaining := p.linksPlanned - p.linksChecked
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	// The page fetch counts as one unit of work, each link check as another;
+	// 100 is reserved for the task saving the result
+	progress := 0
+	if total := 1 + p.linksPlanned; p.pagesFetched > 0 {
+		progress = (1 + p.linksChecked) * 100 / total
+		if progress > 99 {
+			progress = 99
+		}
+	}
+
+	eta := 0
+	if checked := p.linksChecked - p.checkedBefore; checked > 0 && remaining > 0 {
+		perLink := time.Since(p.checkStartedAt) / time.Duration(checked)
+		eta = int((perLink * time.Duration(remaining)).Seconds())
+	}
+
+	return models.CrawlJob{
+		Progress:        progress,
+		PagesFetched:    p.pagesFetched,
+		LinksDiscovered: p.linksDiscovered,
+		LinksChecked:    p.linksChecked,
+		BrokenLinks:     p.brokenLinks,
+		LinksRemaining:  remaining,
+		ETASeconds:      eta,
+	}
+}
@@ -0,0 +1,27 @@
+package crawl_manager
+
+import (
+	"context"
+
+	"sykell-challenge/backend/services/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// traceStep runs a step of the crawl in a span of its own; the outbound
+// requests made during the step are recorded under it
+func (cm *CrawlManager) traceStep(ctx context.Context, name string, step func(context.Context) error) error {
+	stepCtx, span := telemetry.Tracer().Start(ctx, name)
+	defer span.End()
+
+	cm.bindContext(stepCtx)
+	defer cm.bindContext(ctx)
+
+	err := step(stepCtx)
+	span.SetAttributes(
+		attribute.Int("crawl.links_checked", len(cm.data.Links)),
+		attribute.Int("crawl.links_pending", len(cm.pending)),
+	)
+	telemetry.RecordError(span, err)
+	return err
+}
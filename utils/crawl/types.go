@@ -1,6 +1,7 @@
 package crawl
 
 import (
+	"errors"
 	"time"
 
 	"sykell-challenge/backend/models"
@@ -45,10 +46,22 @@ type CrawlResult struct {
 	Data    models.URL
 }
 
+// Crawl outcomes, stored as the final CrawlJob status
+const (
+	OutcomeCompleted = "completed"
+	OutcomeTruncated = "truncated" // A size, link or request limit was hit
+	OutcomeTimeout   = "timeout"   // The job ran out of time
+)
+
+// ErrPaused is the cancellation cause of a job that is being paused
+var ErrPaused = errors.New("crawl paused")
+
 // CrawlData represents data from the crawl process
 type CrawlData struct {
-	MainData  models.URL
-	LinkCount int
+	MainData      models.URL
+	LinkCount     int
+	Outcome       string // One of the Outcome* values
+	OutcomeReason string // Why the crawl was truncated or timed out
 }
 
 // CrawlJobOrExistingData represents either a new crawl job or existing URL data
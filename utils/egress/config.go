@@ -0,0 +1,25 @@
+package egress
+
+import (
+	"time"
+)
+
+// Config holds outbound HTTP configuration shared by the crawler and the link checker
+type Config struct {
+	// Proxies is the global default proxy pool (http, https or socks5 URLs)
+	Proxies []string `yaml:"proxies"`
+	// MaxConsecutiveFailures takes a proxy out of rotation after this many failures in a row
+	MaxConsecutiveFailures int `yaml:"maxConsecutiveFailures"`
+	// FailureCooldown is how long a failing proxy stays out of rotation
+	FailureCooldown time.Duration `yaml:"failureCooldown"`
+	// SSRFAllowlist lists CIDRs, IPs or hostnames exempt from the private address block
+	SSRFAllowlist []string `yaml:"ssrfAllowlist"`
+}
+
+// DefaultConfig returns the default egress configuration
+func DefaultConfig() *Config {
+	return &Config{
+		MaxConsecutiveFailures: 3,
+		FailureCooldown:        time.Minute,
+	}
+}
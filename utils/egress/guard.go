@@ -0,0 +1,208 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BlockedError is returned when an outbound connection targets a forbidden address
+type BlockedError struct {
+	Host   string
+	Reason string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("blocked request to %s: %s", e.Host, e.Reason)
+}
+
+// IsBlocked reports whether err was caused by the SSRF guard
+func IsBlocked(err error) bool {
+	var blocked *BlockedError
+	return errors.As(err, &blocked)
+}
+
+// Guard rejects connections to loopback, private, link-local, cloud metadata
+// and other non-public addresses unless they are explicitly allowlisted
+type Guard struct {
+	allowPrefixes []netip.Prefix
+	allowHosts    map[string]bool
+	resolver      *net.Resolver
+	dialer        *net.Dialer
+}
+
+// blockedPrefixes complements the netip.Addr Is* checks with special-purpose ranges
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT, includes Alibaba's metadata service
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, includes broadcast
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+var (
+	guard     *Guard
+	guardOnce sync.Once
+)
+
+// DefaultGuard returns the guard built from the configured allowlist. Hosts of
+// the global proxy pool are trusted, since operators configure them.
+func DefaultGuard() *Guard {
+	guardOnce.Do(func() {
+		cfg := getConfig()
+		allowlist := append([]string{}, cfg.SSRFAllowlist...)
+		for _, proxy := range cfg.Proxies {
+			if proxyURL, err := url.Parse(proxy); err == nil && proxyURL.Hostname() != "" {
+				allowlist = append(allowlist, proxyURL.Hostname())
+			}
+		}
+		guard = NewGuard(allowlist)
+	})
+	return guard
+}
+
+// NewGuard builds a guard; allowlist entries may be CIDRs, IPs or hostnames
+func NewGuard(allowlist []string) *Guard {
+	g := &Guard{
+		allowHosts: make(map[string]bool),
+		resolver:   net.DefaultResolver,
+		dialer:     &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+	}
+
+	for _, entry := range allowlist {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			g.allowPrefixes = append(g.allowPrefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			g.allowPrefixes = append(g.allowPrefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		} else {
+			g.allowHosts[strings.ToLower(entry)] = true
+		}
+	}
+
+	return g
+}
+
+// CheckURL validates the scheme and every address the URL's host resolves to.
+// Handlers use it to reject bad targets early; DialContext enforces it per connection.
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsedURL.Scheme == "" {
+		parsedURL, err = url.Parse("http://" + rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid URL: %w", err)
+		}
+	}
+	if err := CheckScheme(parsedURL); err != nil {
+		return err
+	}
+
+	_, err = g.resolve(ctx, parsedURL.Hostname())
+	return err
+}
+
+// CheckScheme only allows plain web requests, also after redirects
+func CheckScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return &BlockedError{Host: u.String(), Reason: fmt.Sprintf("scheme %q is not allowed", u.Scheme)}
+	}
+	if u.Hostname() == "" {
+		return &BlockedError{Host: u.String(), Reason: "missing host"}
+	}
+	return nil
+}
+
+// DialContext resolves the host itself and connects to a checked IP, so a
+// DNS answer cannot change between the check and the connection (rebinding)
+func (g *Guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := g.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, addr := range addrs {
+		conn, err := g.dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// resolve returns the host's addresses, failing if any of them is blocked
+func (g *Guard) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		resolved, err := g.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, err
+		}
+		addrs = resolved
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+
+	if g.allowHosts[host] {
+		return addrs, nil
+	}
+
+	for _, addr := range addrs {
+		if reason := g.blockReason(addr.Unmap()); reason != "" {
+			return nil, &BlockedError{Host: host, Reason: reason}
+		}
+	}
+	return addrs, nil
+}
+
+// blockReason explains why addr is not publicly routable, or returns "" if it is allowed
+func (g *Guard) blockReason(addr netip.Addr) string {
+	for _, prefix := range g.allowPrefixes {
+		if prefix.Contains(addr) {
+			return ""
+		}
+	}
+
+	switch {
+	case addr.IsLoopback():
+		return "loopback address"
+	case addr.IsPrivate():
+		return "private network address"
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast():
+		return "link-local address (cloud metadata services live here)"
+	case addr.IsUnspecified():
+		return "unspecified address"
+	case addr.IsMulticast(), addr.IsInterfaceLocalMulticast():
+		return "multicast address"
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return "reserved address range"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package egress
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGuardCheckURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		allowlist []string
+		blocked   bool
+	}{
+		{name: "public address", url: "http://93.184.216.34/", blocked: false},
+		{name: "public IPv6 address", url: "https://[2606:2800:220:1:248:1893:25c8:1946]/", blocked: false},
+		{name: "missing scheme defaults to http", url: "93.184.216.34/page", blocked: false},
+		{name: "loopback", url: "http://127.0.0.1:8080/", blocked: true},
+		{name: "IPv6 loopback", url: "http://[::1]/", blocked: true},
+		{name: "IPv4-mapped IPv6 loopback", url: "http://[::ffff:127.0.0.1]/", blocked: true},
+		{name: "private 10/8", url: "http://10.1.2.3/", blocked: true},
+		{name: "private 172.16/12", url: "http://172.20.0.1/", blocked: true},
+		{name: "private 192.168/16", url: "http://192.168.1.1/", blocked: true},
+		{name: "unique local IPv6", url: "http://[fd00::1]/", blocked: true},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data/", blocked: true},
+		{name: "carrier-grade NAT", url: "http://100.100.100.200/", blocked: true},
+		{name: "unspecified", url: "http://0.0.0.0/", blocked: true},
+		{name: "this network", url: "http://0.1.2.3/", blocked: true},
+		{name: "multicast", url: "http://224.0.0.1/", blocked: true},
+		{name: "documentation range", url: "http://203.0.113.10/", blocked: true},
+		{name: "broadcast", url: "http://255.255.255.255/", blocked: true},
+		{name: "file scheme", url: "file:///etc/passwd", blocked: true},
+		{name: "gopher scheme", url: "gopher://93.184.216.34/", blocked: true},
+		{name: "allowlisted CIDR", url: "http://10.1.2.3/", allowlist: []string{"10.0.0.0/8"}, blocked: false},
+		{name: "outside allowlisted CIDR", url: "http://10.1.2.3/", allowlist: []string{"10.2.0.0/16"}, blocked: true},
+		{name: "allowlisted IP", url: "http://192.168.1.1/", allowlist: []string{"192.168.1.1"}, blocked: false},
+		{name: "other IP than allowlisted", url: "http://192.168.1.2/", allowlist: []string{"192.168.1.1"}, blocked: true},
+		{name: "allowlisted host name", url: "http://localhost/", allowlist: []string{"LocalHost"}, blocked: false},
+		{name: "host name resolving to loopback", url: "http://localhost/", blocked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewGuard(tt.allowlist).CheckURL(context.Background(), tt.url)
+			if tt.blocked && !IsBlocked(err) {
+				t.Fatalf("CheckURL(%q) = %v, want a BlockedError", tt.url, err)
+			}
+			if !tt.blocked && err != nil {
+				t.Fatalf("CheckURL(%q) = %v, want nil", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestGuardDialContextRejectsBlockedAddress(t *testing.T) {
+	_, err := NewGuard(nil).DialContext(context.Background(), "tcp", "127.0.0.1:1")
+	if !IsBlocked(err) {
+		t.Fatalf("DialContext() = %v, want a BlockedError", err)
+	}
+}
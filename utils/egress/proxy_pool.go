@@ -0,0 +1,254 @@
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Proxy is an outbound proxy with failure tracking shared across all pools
+type Proxy struct {
+	URL *url.URL
+
+	successes           atomic.Int64
+	failures            atomic.Int64
+	consecutiveFailures atomic.Int32
+	disabledUntil       atomic.Int64 // Unix nanoseconds
+}
+
+// ProxyStats is a snapshot of a proxy's health
+type ProxyStats struct {
+	URL                 string    `json:"url"`
+	Successes           int64     `json:"successes"`
+	Failures            int64     `json:"failures"`
+	ConsecutiveFailures int32     `json:"consecutiveFailures"`
+	DisabledUntil       time.Time `json:"disabledUntil,omitempty"`
+}
+
+// ProxyPool hands out proxies round-robin, skipping proxies in failure cooldown
+type ProxyPool struct {
+	proxies   []*Proxy
+	next      atomic.Uint64
+	lastUsed  atomic.Int64      // Unix nanoseconds
+	transport http.RoundTripper // Shared by every crawl using the pool
+	base      *http.Transport
+}
+
+// poolIdleTimeout is how long a crawl's own proxy pool stays cached after its
+// last request; its proxies leave the registry with it
+const poolIdleTimeout = 10 * time.Minute
+
+var (
+	config      *Config
+	configOnce  sync.Once
+	registry    = make(map[string]*Proxy)
+	pools       = make(map[string]*ProxyPool) // Per-crawl pools, keyed by their proxy URLs
+	lastPrune   time.Time
+	registryMux sync.Mutex
+	defaultPool *ProxyPool
+	defaultErr  error
+	defaultOnce sync.Once
+)
+
+// Configure sets the egress settings; call it before the first outbound request
+func Configure(egressConfig *Config) {
+	config = egressConfig
+}
+
+func getConfig() *Config {
+	configOnce.Do(func() {
+		if config == nil {
+			config = DefaultConfig()
+		}
+	})
+	return config
+}
+
+// DefaultPool returns the global proxy pool, or nil when no proxies are configured
+func DefaultPool() (*ProxyPool, error) {
+	defaultOnce.Do(func() {
+		registryMux.Lock()
+		defer registryMux.Unlock()
+		defaultPool, defaultErr = newProxyPoolLocked(getConfig().Proxies)
+	})
+	return defaultPool, defaultErr
+}
+
+// PoolFor returns the pool a crawl should use: its own proxies when set,
+// no proxy when disabled, and the global default otherwise
+func PoolFor(proxies []string, noProxy bool) (*ProxyPool, error) {
+	if noProxy {
+		return nil, nil
+	}
+	if len(proxies) > 0 {
+		return cachedPool(proxies)
+	}
+	return DefaultPool()
+}
+
+// cachedPool returns the pool for a crawl's own proxies, reusing it and its
+// connections across crawls with the same list
+func cachedPool(rawURLs []string) (*ProxyPool, error) {
+	key := strings.Join(rawURLs, "\n")
+
+	registryMux.Lock()
+	defer registryMux.Unlock()
+
+	now := time.Now()
+	pruneLocked(now)
+
+	if pool, exists := pools[key]; exists {
+		pool.lastUsed.Store(now.UnixNano())
+		return pool, nil
+	}
+
+	pool, err := newProxyPoolLocked(rawURLs)
+	if err != nil {
+		return nil, err
+	}
+	pool.lastUsed.Store(now.UnixNano())
+	pools[key] = pool
+	return pool, nil
+}
+
+// newProxyPoolLocked builds a pool from proxy URLs; it returns nil for an empty
+// list. Proxies are shared by URL, so failures are tracked across pools.
+// The caller holds registryMux.
+func newProxyPoolLocked(rawURLs []string) (*ProxyPool, error) {
+	if len(rawURLs) == 0 {
+		return nil, nil
+	}
+
+	pool := &ProxyPool{}
+	for _, rawURL := range rawURLs {
+		proxyURL, err := ParseProxyURL(rawURL)
+		if err != nil {
+			return nil, err
+		}
+
+		proxy, exists := registry[proxyURL.String()]
+		if !exists {
+			proxy = &Proxy{URL: proxyURL}
+			registry[proxyURL.String()] = proxy
+		}
+		pool.proxies = append(pool.proxies, proxy)
+	}
+	pool.transport, pool.base = newTransport(pool)
+
+	return pool, nil
+}
+
+// pruneLocked drops per-crawl pools idle for longer than poolIdleTimeout,
+// closing their connections, and forgets proxies no remaining pool uses
+func pruneLocked(now time.Time) {
+	if now.Sub(lastPrune) < poolIdleTimeout {
+		return
+	}
+	lastPrune = now
+
+	inUse := make(map[*Proxy]bool)
+	if defaultPool != nil {
+		for _, proxy := range defaultPool.proxies {
+			inUse[proxy] = true
+		}
+	}
+	for key, pool := range pools {
+		if now.Sub(time.Unix(0, pool.lastUsed.Load())) > poolIdleTimeout {
+			delete(pools, key)
+			pool.base.CloseIdleConnections()
+			continue
+		}
+		for _, proxy := range pool.proxies {
+			inUse[proxy] = true
+		}
+	}
+
+	for key, proxy := range registry {
+		if !inUse[proxy] {
+			delete(registry, key)
+		}
+	}
+}
+
+// Stats returns a snapshot of every proxy known to this process
+func Stats() []ProxyStats {
+	registryMux.Lock()
+	defer registryMux.Unlock()
+
+	stats := make([]ProxyStats, 0, len(registry))
+	for _, proxy := range registry {
+		stats = append(stats, proxy.Stats())
+	}
+	slices.SortFunc(stats, func(a, b ProxyStats) int { return strings.Compare(a.URL, b.URL) })
+	return stats
+}
+
+// ParseProxyURL validates a proxy URL; http, https and socks5 proxies are supported
+func ParseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, errors.New("proxy URL must include a host")
+	}
+
+	return proxyURL, nil
+}
+
+// Next returns the next healthy proxy. When every proxy is cooling down it
+// still returns one, so requests degrade instead of failing outright.
+func (p *ProxyPool) Next() *Proxy {
+	now := time.Now().UnixNano()
+	p.lastUsed.Store(now)
+	start := p.next.Add(1) - 1
+
+	for i := 0; i < len(p.proxies); i++ {
+		proxy := p.proxies[(start+uint64(i))%uint64(len(p.proxies))]
+		if proxy.disabledUntil.Load() <= now {
+			return proxy
+		}
+	}
+
+	return p.proxies[start%uint64(len(p.proxies))]
+}
+
+// Stats returns a snapshot of the proxy's health; credentials are redacted
+func (p *Proxy) Stats() ProxyStats {
+	stats := ProxyStats{
+		URL:                 p.URL.Redacted(),
+		Successes:           p.successes.Load(),
+		Failures:            p.failures.Load(),
+		ConsecutiveFailures: p.consecutiveFailures.Load(),
+	}
+	if until := p.disabledUntil.Load(); until > time.Now().UnixNano() {
+		stats.DisabledUntil = time.Unix(0, until)
+	}
+	return stats
+}
+
+func (p *Proxy) recordSuccess() {
+	p.successes.Add(1)
+	p.consecutiveFailures.Store(0)
+}
+
+func (p *Proxy) recordFailure() {
+	p.failures.Add(1)
+	cfg := getConfig()
+	if int(p.consecutiveFailures.Add(1)) >= cfg.MaxConsecutiveFailures {
+		p.disabledUntil.Store(time.Now().Add(cfg.FailureCooldown).UnixNano())
+		p.consecutiveFailures.Store(0)
+	}
+}
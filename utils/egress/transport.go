@@ -0,0 +1,100 @@
+package egress
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+type proxyContextKey struct{}
+
+// proxyTransport picks a proxy from the pool for every request and records
+// whether the request through it succeeded
+type proxyTransport struct {
+	base *http.Transport
+	pool *ProxyPool
+}
+
+var (
+	directTransport     http.RoundTripper
+	directTransportOnce sync.Once
+)
+
+// TransportFor returns the transport used for outbound traffic through pool.
+// Every connection goes through the SSRF guard. A nil pool keeps the
+// standard HTTP_PROXY environment handling. Transports are shared, so
+// callers keep their connections pooled across crawls.
+func TransportFor(pool *ProxyPool) http.RoundTripper {
+	if pool != nil {
+		return pool.transport
+	}
+	directTransportOnce.Do(func() {
+		directTransport, _ = newTransport(nil)
+	})
+	return directTransport
+}
+
+// newTransport builds a traced transport for pool, along with the underlying
+// transport whose idle connections can be closed
+func newTransport(pool *ProxyPool) (http.RoundTripper, *http.Transport) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.DialContext = DefaultGuard().DialContext
+	if pool == nil {
+		return traced(&envProxyTransport{base: base}), base
+	}
+
+	base.Proxy = proxyFromContext
+	return traced(&proxyTransport{base: base, pool: pool}), base
+}
+
+// traced records a client span for every request. Trace headers are not
+// sent, since the targets are third-party servers.
+func traced(transport http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(transport, otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()))
+}
+
+func (t *proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Only the proxy is dialed locally, so check the target host up front
+	if err := DefaultGuard().CheckURL(req.Context(), req.URL.String()); err != nil {
+		return nil, err
+	}
+
+	proxy := t.pool.Next()
+	req = req.WithContext(context.WithValue(req.Context(), proxyContextKey{}, proxy))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode == http.StatusProxyAuthRequired {
+		proxy.recordFailure()
+	} else {
+		proxy.recordSuccess()
+	}
+
+	return resp, err
+}
+
+// envProxyTransport keeps the HTTP_PROXY environment handling. A request
+// sent through such a proxy only dials the proxy locally, so its target
+// host is checked up front.
+type envProxyTransport struct {
+	base *http.Transport
+}
+
+func (t *envProxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if proxy, err := t.base.Proxy(req); err == nil && proxy != nil {
+		if err := DefaultGuard().CheckURL(req.Context(), req.URL.String()); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+func proxyFromContext(req *http.Request) (*url.URL, error) {
+	if proxy, ok := req.Context().Value(proxyContextKey{}).(*Proxy); ok {
+		return proxy.URL, nil
+	}
+	return nil, nil
+}
@@ -1,14 +0,0 @@
-package utils
-
-import (
-	"os"
-)
-
-func GetEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-
-	return value
-}
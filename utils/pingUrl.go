@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sykell-challenge/backend/utils/egress"
 	"time"
 )
 
@@ -12,6 +13,12 @@ import (
 type PingURLOptions struct {
 	Timeout      time.Duration
 	MaxRedirects int
+	UserAgent    string
+	Headers      map[string]string
+	Cookies      []*http.Cookie
+	Username     string // Basic-auth credentials, sent when Username is set
+	Password     string
+	Transport    http.RoundTripper // Outbound transport (proxies); nil uses the guarded default transport
 }
 
 // DefaultPingOptions returns default options for URL ping
@@ -19,6 +26,7 @@ func DefaultPingOptions() PingURLOptions {
 	return PingURLOptions{
 		Timeout:      10 * time.Second,
 		MaxRedirects: 5,
+		UserAgent:    "Mozilla/5.0 (compatible; URLCrawler/1.0)",
 	}
 }
 
@@ -28,11 +36,17 @@ type PingURLResult struct {
 	StatusCode   int
 	ResponseTime time.Duration
 	Error        string
+	Err          error  // Underlying request error, if any
 	FinalURL     string // URL after redirects
 }
 
 // PingURL checks if a URL is available and accessible
 func PingURL(targetURL string, options ...PingURLOptions) PingURLResult {
+	return PingURLContext(context.Background(), targetURL, options...)
+}
+
+// PingURLContext is PingURL with a context that aborts the check when cancelled
+func PingURLContext(parent context.Context, targetURL string, options ...PingURLOptions) PingURLResult {
 	var opts PingURLOptions
 	if len(options) > 0 {
 		opts = options[0]
@@ -57,19 +71,44 @@ func PingURL(targetURL string, options ...PingURLOptions) PingURLResult {
 		targetURL = parsedURL.String()
 	}
 
+	// Pings without a transport go through the guarded default, so they
+	// cannot reach private addresses either
+	transport := opts.Transport
+	if transport == nil {
+		transport = egress.TransportFor(nil)
+	}
+
 	// Create HTTP client with timeout and redirect policy
 	client := &http.Client{
-		Timeout: opts.Timeout,
+		Transport: transport,
+		Timeout:   opts.Timeout,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			if opts.MaxRedirects == 0 {
+				// Redirects disabled: report the redirect response itself
+				return http.ErrUseLastResponse
+			}
 			if len(via) >= opts.MaxRedirects {
 				return fmt.Errorf("too many redirects (%d)", len(via))
 			}
+
+			// The client copies every header of the first request onto each
+			// redirect, so drop credentials and custom headers off its host
+			if req.URL.Host != via[0].URL.Host {
+				req.Header.Del("Authorization")
+				req.Header.Del("Cookie")
+				for name := range opts.Headers {
+					req.Header.Del(name)
+				}
+			}
 			return nil
 		},
 	}
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	ctx, cancel := context.WithTimeout(parent, opts.Timeout)
 	defer cancel()
 
 	// Create request
@@ -80,7 +119,21 @@ func PingURL(targetURL string, options ...PingURLOptions) PingURLResult {
 	}
 
 	// Set a realistic User-Agent to avoid blocking
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; URLCrawler/1.0)")
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultPingOptions().UserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	for name, value := range opts.Headers {
+		req.Header.Set(name, value)
+	}
+	for _, cookie := range opts.Cookies {
+		req.AddCookie(cookie)
+	}
+	if opts.Username != "" {
+		req.SetBasicAuth(opts.Username, opts.Password)
+	}
 
 	// Record start time
 	startTime := time.Now()
@@ -91,14 +144,15 @@ func PingURL(targetURL string, options ...PingURLOptions) PingURLResult {
 	// Calculate response time
 	result.ResponseTime = time.Since(startTime)
 
-	if err != nil {
+	if err != nil && parent.Err() == nil {
 		// Try with GET if HEAD fails (some servers don't support HEAD)
 		req.Method = "GET"
 		resp, err = client.Do(req)
-		if err != nil {
-			result.Error = fmt.Sprintf("Request failed: %v", err)
-			return result
-		}
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("Request failed: %v", err)
+		result.Err = err
+		return result
 	}
 	defer resp.Body.Close()
 
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPingURLRedirectHeaders(t *testing.T) {
+	received := make(chan http.Header, 1)
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	}))
+	defer other.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	opts := DefaultPingOptions()
+	opts.Transport = http.DefaultTransport // The guarded transport refuses loopback test servers
+	opts.Headers = map[string]string{"X-Api-Key": "header-value"}
+	opts.Cookies = []*http.Cookie{{Name: "session", Value: "cookie-value"}}
+	opts.Username, opts.Password = "alice", "basic-password"
+
+	if result := PingURL(origin.URL, opts); !result.Available {
+		t.Fatalf("PingURL() = %+v, want available", result)
+	}
+
+	header := <-received
+	for _, name := range []string{"X-Api-Key", "Cookie", "Authorization"} {
+		if value := header.Get(name); value != "" {
+			t.Errorf("redirect to another host sent %s: %q", name, value)
+		}
+	}
+	if header.Get("User-Agent") != opts.UserAgent {
+		t.Errorf("User-Agent = %q, want %q", header.Get("User-Agent"), opts.UserAgent)
+	}
+}
@@ -0,0 +1,98 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Prefix marks values produced by Encrypt
+const Prefix = "enc:v1:"
+
+var (
+	passphrase string
+	aead       cipher.AEAD
+	aeadOnce   sync.Once
+	aeadErr    error
+)
+
+// Init sets the passphrase the encryption key is derived from; call it
+// before the first Encrypt or Decrypt
+func Init(key string) {
+	passphrase = key
+}
+
+// getAEAD lazily builds the AES-256-GCM cipher from the passphrase
+func getAEAD() (cipher.AEAD, error) {
+	aeadOnce.Do(func() {
+		if passphrase == "" {
+			aeadErr = errors.New("encryption key is not configured")
+			return
+		}
+		key := sha256.Sum256([]byte(passphrase))
+
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			aeadErr = err
+			return
+		}
+		aead, aeadErr = cipher.NewGCM(block)
+	})
+
+	return aead, aeadErr
+}
+
+// Encrypt encrypts plaintext and returns a prefixed, base64 encoded ciphertext
+func Encrypt(plaintext string) (string, error) {
+	gcm, err := getAEAD()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt
+func Decrypt(ciphertext string) (string, error) {
+	if !IsEncrypted(ciphertext) {
+		return "", errors.New("value is not encrypted")
+	}
+
+	gcm, err := getAEAD()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, Prefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid ciphertext: too short")
+	}
+
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt value")
+	}
+
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
@@ -0,0 +1,69 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sykell-challenge/backend/models"
+	"sykell-challenge/backend/utils/egress"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// forbiddenCrawlHeaders are managed by the HTTP client or by dedicated options
+var forbiddenCrawlHeaders = map[string]bool{
+	"Host":                true,
+	"Content-Length":      true,
+	"Transfer-Encoding":   true,
+	"Connection":          true,
+	"Cookie":              true,
+	"User-Agent":          true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+}
+
+// ValidateCrawlOptions validates per-crawl request options
+func ValidateCrawlOptions(opts models.CrawlOptions) error {
+	if strings.ContainsAny(opts.UserAgent, "\r\n") {
+		return errors.New("user agent must not contain line breaks")
+	}
+
+	for name, value := range opts.Headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("invalid value for header %q", name)
+		}
+		if forbiddenCrawlHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Errorf("header %q cannot be set, use the dedicated option instead", name)
+		}
+	}
+
+	for _, cookie := range opts.Cookies {
+		if err := (&http.Cookie{Name: cookie.Name, Value: cookie.Value}).Valid(); err != nil {
+			return fmt.Errorf("invalid cookie %q: %v", cookie.Name, err)
+		}
+	}
+
+	if opts.BasicAuth != nil {
+		if opts.BasicAuth.Username == "" {
+			return errors.New("basic auth username is required")
+		}
+		if strings.Contains(opts.BasicAuth.Username, ":") {
+			return errors.New("basic auth username must not contain ':'")
+		}
+	}
+
+	if opts.NoProxy && len(opts.Proxies) > 0 {
+		return errors.New("proxies cannot be set together with noProxy")
+	}
+	for _, proxy := range opts.Proxies {
+		if _, err := egress.ParseProxyURL(proxy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}